@@ -13,13 +13,19 @@ import (
 	"gorm.io/gorm"
 
 	"hrms/db"
+	"hrms/internal/auth"
 	"hrms/internal/clients/boundary"
 	"hrms/internal/clients/idgen"
+	"hrms/internal/clients/individual"
+	"hrms/internal/clients/user"
 	hrmsConfig "hrms/internal/config"
 	"hrms/internal/handler"
+	"hrms/internal/pii"
 	"hrms/internal/repository"
 	"hrms/internal/router"
+	"hrms/internal/scheduler"
 	hrmsService "hrms/internal/service"
+	"hrms/internal/validator"
 )
 
 func main() {
@@ -40,9 +46,40 @@ func main() {
 	}
 	logger.Info("Database connection established")
 
+	// Initialize the key ring used to encrypt personal data at rest
+	piiKeys, err := pii.NewKeyRing(pii.Config{
+		Keys:        cfg.PII.EncryptionKeys,
+		ActiveKeyID: cfg.PII.ActiveKeyID,
+		HashKey:     cfg.PII.HashKey,
+	})
+	if err != nil {
+		logger.Fatalf("Failed to initialize PII key ring: %v", err)
+	}
+	pii.RegisterSerializer(piiKeys)
+
+	// Encrypt personal data written before encryption was introduced. Encrypted fields no
+	// longer read plaintext, so the service must not serve requests until this has run.
+	backfill, err := repository.BackfillPII(context.Background(), dbConn, piiKeys)
+	if err != nil {
+		logger.Fatalf("Failed to encrypt legacy personal data: %v", err)
+	}
+	if backfill.Employees > 0 || backfill.Versions > 0 {
+		logger.Infof("Encrypted legacy personal data of %d employees and %d history versions", backfill.Employees, backfill.Versions)
+	}
+	for _, id := range backfill.DuplicateMobile {
+		logger.WithField("employee_id", id).Warn("Mobile number is shared with another employee of the tenant; it cannot be searched until resolved")
+	}
+
 	// Initialize repositories
-	employeeRepo := repository.NewEmployeeRepository(dbConn)
-	jurisdictionRepo := repository.NewJurisdictionRepository(dbConn)
+	auditRepo := repository.NewAuditRepository(dbConn)
+	employeeRepo := repository.NewEmployeeRepository(dbConn, piiKeys, auditRepo)
+	jurisdictionRepo := repository.NewJurisdictionRepository(dbConn, auditRepo)
+	assignmentRepo := repository.NewAssignmentRepository(dbConn)
+	serviceHistoryRepo := repository.NewServiceHistoryRepository(dbConn)
+	qualificationRepo := repository.NewQualificationRepository(dbConn)
+	deactivationRepo := repository.NewDeactivationRepository(dbConn)
+	pendingActionRepo := repository.NewPendingActionRepository(dbConn)
+	uow := repository.NewUnitOfWork(dbConn)
 
 	// Initialize ID generation client
 	idGenClient := idgen.NewClient(idgen.Config{
@@ -52,26 +89,103 @@ func main() {
 		IDGenName: cfg.IDGen.IDGenName,
 	})
 
-	boundaryClient := boundary.NewClient(cfg.Boundary.BaseURL)
+	boundaryClient := boundary.NewCachingClient(
+		boundary.NewClient(cfg.Boundary.BaseURL),
+		time.Duration(cfg.Boundary.CacheTTLSeconds)*time.Second,
+	)
+
+	// Initialize Individual client; without it individualId is stored unchecked
+	var individualClient individual.Client
+	if cfg.Individual.Enabled {
+		individualClient = individual.NewClient(individual.Config{
+			Host:    cfg.Individual.Host,
+			Path:    cfg.Individual.Path,
+			Timeout: time.Duration(cfg.Individual.Timeout) * time.Second,
+		})
+	}
+
+	// Initialize user client; without it employees are created without login accounts
+	var userClient user.Client
+	var passwordGenerator *user.PasswordGenerator
+	if cfg.User.Enabled {
+		userClient = user.NewClient(user.Config{
+			Host:    cfg.User.Host,
+			Path:    cfg.User.Path,
+			Timeout: time.Duration(cfg.User.Timeout) * time.Second,
+		})
+		passwordGenerator, err = user.NewPasswordGenerator(cfg.User.DefaultPasswordLength)
+		if err != nil {
+			logger.Fatalf("Invalid password policy: %v", err)
+		}
+	}
+
+	employeeValidator := validator.NewEmployeeValidator(employeeRepo, cfg)
 
-	// First, create employee service with a nil jurisdiction service
-	employeeSvc := hrmsService.NewEmployeeService(employeeRepo, nil, idGenClient)
+	assignmentSvc := hrmsService.NewAssignmentService(assignmentRepo)
+	serviceHistorySvc := hrmsService.NewServiceHistoryService(serviceHistoryRepo)
+	qualificationSvc := hrmsService.NewQualificationService(qualificationRepo)
 
-	// Then create jurisdiction service with the employee service
+	// The jurisdiction service checks employees through the repository, so it is created first
 	jurisdictionSvc := hrmsService.NewJurisdictionService(
 		jurisdictionRepo,
-		employeeSvc,
+		employeeRepo,
 		boundaryClient,
+		cfg.Boundary.ValidationMode == hrmsConfig.BoundaryValidationStrict,
+		uow,
 	)
-	// Now update the employee service with the jurisdiction service
-	employeeSvc = hrmsService.NewEmployeeService(employeeRepo, jurisdictionSvc, idGenClient)
+	employeeSvc := hrmsService.NewEmployeeService(employeeRepo, jurisdictionSvc, idGenClient, assignmentSvc, serviceHistorySvc, qualificationSvc, deactivationRepo, pendingActionRepo, employeeValidator, uow, individualClient, userClient, passwordGenerator, auditRepo)
+
+	// Start the scheduler for future-dated status changes
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	if cfg.Scheduler.Enabled {
+		statusScheduler := scheduler.NewStatusScheduler(employeeSvc, time.Duration(cfg.Scheduler.IntervalSeconds)*time.Second, logger)
+		statusScheduler.Start(schedulerCtx)
+	}
+
+	// Fill the hierarchy and boundary type of jurisdictions written before they were
+	// stored. It needs the boundary service, so it runs in the background and is retried
+	// on the next start when the service is unavailable.
+	go func() {
+		result, err := jurisdictionSvc.BackfillBoundaryDetails(schedulerCtx)
+		if err != nil {
+			logger.WithError(err).Warn("Failed to fill jurisdiction boundary details; retrying on the next start")
+			return
+		}
+		if result.Filled > 0 {
+			logger.Infof("Filled the hierarchy and boundary type of %d jurisdictions", result.Filled)
+		}
+		for _, id := range result.UnknownBoundary {
+			logger.WithField("jurisdiction_id", id).Warn("Jurisdiction boundary is unknown to the boundary service; its hierarchy and boundary type stay empty")
+		}
+	}()
 
 	// Initialize handlers
-	employeeHandler := handler.NewEmployeeHandler(employeeSvc, logger)
+	// Open search is only restricted when callers are authenticated
+	var openSearchRoles []string
+	if cfg.Auth.Enabled {
+		openSearchRoles = cfg.Search.OpenSearchRoles
+	}
+	employeeHandler := handler.NewEmployeeHandler(employeeSvc, logger, cfg.PII.UnmaskRoles, openSearchRoles)
 	jurisdictionHandler := handler.NewJurisdictionHandler(jurisdictionSvc, logger)
 
+	// Initialize the token verifier; without it every request is trusted
+	var verifier *auth.Verifier
+	if cfg.Auth.Enabled {
+		verifier, err = auth.NewVerifier(auth.Config{
+			SharedKey: cfg.Auth.SharedKey,
+			JWKSFile:  cfg.Auth.JWKSFile,
+			Issuer:    cfg.Auth.Issuer,
+			Audience:  cfg.Auth.Audience,
+			Leeway:    time.Duration(cfg.Auth.LeewaySeconds) * time.Second,
+		})
+		if err != nil {
+			logger.Fatalf("Failed to initialize token verifier: %v", err)
+		}
+	}
+
 	// Setup router
-	r := router.SetupRouter(cfg, employeeHandler, jurisdictionHandler, logger)
+	r := router.SetupRouter(cfg, verifier, employeeHandler, jurisdictionHandler, logger)
 
 	// Start server in a goroutine
 	server := &http.Server{
@@ -93,6 +207,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	logger.Info("Shutting down server...")
+	stopScheduler()
 
 	// The context is used to inform the server it has 5 seconds to finish
 	// the request it is currently handling
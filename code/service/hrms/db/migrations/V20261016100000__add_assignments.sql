@@ -0,0 +1,27 @@
+-- Employee assignments: time-bound department/designation postings
+
+CREATE TABLE IF NOT EXISTS eg_hrms_assignment_v3 (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    -- Assignments are never hard-deleted, so deleting an employee that has any is refused
+    employee_id UUID NOT NULL REFERENCES eg_hrms_employee_v3(id) ON DELETE RESTRICT,
+    department VARCHAR(64) NOT NULL,
+    designation VARCHAR(64) NOT NULL,
+    reporting_to VARCHAR(64),
+    is_hod BOOLEAN DEFAULT FALSE,
+    is_current_assignment BOOLEAN DEFAULT FALSE,
+    from_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    to_date TIMESTAMP WITH TIME ZONE,
+    tenant_id VARCHAR(64) NOT NULL,
+    created_by VARCHAR(64) NOT NULL,
+    last_modified_by VARCHAR(64),
+    created_time BIGINT NOT NULL,
+    last_modified_time BIGINT,
+    CONSTRAINT chk_assignment_period CHECK (to_date IS NULL OR to_date > from_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignment_employee_tenant ON eg_hrms_assignment_v3 (employee_id, tenant_id);
+
+-- At most one current assignment per employee
+CREATE UNIQUE INDEX IF NOT EXISTS uk_assignment_current
+    ON eg_hrms_assignment_v3 (employee_id)
+    WHERE is_current_assignment;
@@ -0,0 +1,19 @@
+-- Employee service history: legacy record of past postings, periods may overlap
+
+CREATE TABLE IF NOT EXISTS eg_hrms_service_history_v3 (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    employee_id UUID NOT NULL REFERENCES eg_hrms_employee_v3(id) ON DELETE CASCADE,
+    service_status VARCHAR(64),
+    service_from TIMESTAMP WITH TIME ZONE,
+    service_to TIMESTAMP WITH TIME ZONE,
+    order_no VARCHAR(128),
+    location VARCHAR(256),
+    is_current_position BOOLEAN DEFAULT FALSE,
+    tenant_id VARCHAR(64) NOT NULL,
+    created_by VARCHAR(64) NOT NULL,
+    last_modified_by VARCHAR(64),
+    created_time BIGINT NOT NULL,
+    last_modified_time BIGINT
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_history_employee_tenant ON eg_hrms_service_history_v3 (employee_id, tenant_id);
@@ -0,0 +1,33 @@
+-- Employee educational details and departmental tests
+
+CREATE TABLE IF NOT EXISTS eg_hrms_educational_details_v3 (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    employee_id UUID NOT NULL REFERENCES eg_hrms_employee_v3(id) ON DELETE CASCADE,
+    degree VARCHAR(128) NOT NULL,
+    specialization VARCHAR(128),
+    university VARCHAR(256),
+    year_of_passing INTEGER NOT NULL,
+    remarks VARCHAR(512),
+    tenant_id VARCHAR(64) NOT NULL,
+    created_by VARCHAR(64) NOT NULL,
+    last_modified_by VARCHAR(64),
+    created_time BIGINT NOT NULL,
+    last_modified_time BIGINT
+);
+
+CREATE INDEX IF NOT EXISTS idx_educational_details_employee_tenant ON eg_hrms_educational_details_v3 (employee_id, tenant_id);
+
+CREATE TABLE IF NOT EXISTS eg_hrms_departmental_tests_v3 (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    employee_id UUID NOT NULL REFERENCES eg_hrms_employee_v3(id) ON DELETE CASCADE,
+    test VARCHAR(256) NOT NULL,
+    year_of_passing INTEGER NOT NULL,
+    remarks VARCHAR(512),
+    tenant_id VARCHAR(64) NOT NULL,
+    created_by VARCHAR(64) NOT NULL,
+    last_modified_by VARCHAR(64),
+    created_time BIGINT NOT NULL,
+    last_modified_time BIGINT
+);
+
+CREATE INDEX IF NOT EXISTS idx_departmental_tests_employee_tenant ON eg_hrms_departmental_tests_v3 (employee_id, tenant_id);
@@ -0,0 +1,16 @@
+-- Append-only history of employee deactivations and reactivations
+
+CREATE TABLE IF NOT EXISTS eg_hrms_deactivation_details (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    employee_id UUID NOT NULL REFERENCES eg_hrms_employee_v3(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL,
+    reason VARCHAR(256) NOT NULL,
+    effective_from TIMESTAMP WITH TIME ZONE,
+    remarks TEXT,
+    tenant_id VARCHAR(64) NOT NULL,
+    created_by VARCHAR(64) NOT NULL,
+    created_time BIGINT NOT NULL,
+    CONSTRAINT chk_deactivation_action CHECK (action IN ('DEACTIVATION', 'REACTIVATION'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_deactivation_details_employee_tenant ON eg_hrms_deactivation_details (employee_id, tenant_id);
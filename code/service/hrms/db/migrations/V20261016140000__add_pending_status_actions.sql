@@ -0,0 +1,26 @@
+-- Future-dated deactivations and reactivations awaiting the in-process scheduler
+
+CREATE TABLE IF NOT EXISTS eg_hrms_pending_status_action (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    employee_id UUID NOT NULL REFERENCES eg_hrms_employee_v3(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL,
+    reason VARCHAR(256) NOT NULL,
+    effective_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    remarks TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    failure_reason TEXT,
+    tenant_id VARCHAR(64) NOT NULL,
+    created_by VARCHAR(64) NOT NULL,
+    created_time BIGINT NOT NULL,
+    last_modified_time BIGINT,
+    CONSTRAINT chk_pending_action CHECK (action IN ('DEACTIVATION', 'REACTIVATION')),
+    CONSTRAINT chk_pending_action_status CHECK (status IN ('PENDING', 'APPLIED', 'CANCELLED', 'FAILED'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_pending_action_employee_tenant ON eg_hrms_pending_status_action (employee_id, tenant_id);
+
+-- Used by the scheduler to pick up due actions
+CREATE INDEX IF NOT EXISTS idx_pending_action_due ON eg_hrms_pending_status_action (effective_from) WHERE status = 'PENDING';
+
+-- Only one scheduled status change per employee at a time
+CREATE UNIQUE INDEX IF NOT EXISTS uk_pending_action_employee ON eg_hrms_pending_status_action (employee_id) WHERE status = 'PENDING';
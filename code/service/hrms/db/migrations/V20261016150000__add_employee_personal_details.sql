@@ -0,0 +1,14 @@
+-- Personal details of an employee
+
+ALTER TABLE eg_hrms_employee_v3
+    ADD COLUMN IF NOT EXISTS mobile_number VARCHAR(16),
+    ADD COLUMN IF NOT EXISTS email VARCHAR(128),
+    ADD COLUMN IF NOT EXISTS date_of_birth TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS gender VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS pan_number VARCHAR(10),
+    ADD COLUMN IF NOT EXISTS aadhaar_number VARCHAR(12),
+    ADD COLUMN IF NOT EXISTS date_of_retirement TIMESTAMP WITH TIME ZONE;
+
+-- No two employees of a tenant may share a phone number
+CREATE UNIQUE INDEX IF NOT EXISTS uk_employee_mobile_tenant ON eg_hrms_employee_v3 (mobile_number, tenant_id)
+    WHERE mobile_number IS NOT NULL AND mobile_number <> '';
@@ -0,0 +1,15 @@
+-- Personal identifiers are stored encrypted by the application (enc:<keyId>:<ciphertext>),
+-- which does not fit the plaintext column sizes. Phone lookups and the per-tenant
+-- uniqueness rule use a deterministic HMAC of the mobile number instead.
+
+ALTER TABLE eg_hrms_employee_v3
+    ALTER COLUMN mobile_number TYPE TEXT,
+    ALTER COLUMN email TYPE TEXT,
+    ALTER COLUMN pan_number TYPE TEXT,
+    ALTER COLUMN aadhaar_number TYPE TEXT,
+    ADD COLUMN IF NOT EXISTS mobile_number_hash VARCHAR(64);
+
+DROP INDEX IF EXISTS uk_employee_mobile_tenant;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uk_employee_mobile_hash_tenant ON eg_hrms_employee_v3 (mobile_number_hash, tenant_id)
+    WHERE mobile_number_hash IS NOT NULL AND mobile_number_hash <> '';
@@ -0,0 +1,9 @@
+-- Structured jurisdictions: hierarchy type, boundary type and boundary.
+-- boundary_relation keeps the ancestor path from the root of the hierarchy down to the boundary.
+
+ALTER TABLE eg_hrms_jurisdiction_v3
+    ADD COLUMN IF NOT EXISTS hierarchy VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS boundary_type VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS boundary VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_jurisdiction_boundary ON eg_hrms_jurisdiction_v3 (boundary, tenant_id);
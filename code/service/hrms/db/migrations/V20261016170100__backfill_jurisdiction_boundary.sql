@@ -0,0 +1,10 @@
+-- One-off back-fill of the boundary column for jurisdictions written before it existed.
+-- The boundary is the last code of the boundary_relation path. Hierarchy and boundary
+-- type are not part of the stored path; they are filled from the boundary service the
+-- next time the jurisdiction's boundary is written.
+
+UPDATE eg_hrms_jurisdiction_v3
+SET boundary = boundary_relation ->> (jsonb_array_length(boundary_relation) - 1)
+WHERE boundary IS NULL
+  AND jsonb_typeof(boundary_relation) = 'array'
+  AND jsonb_array_length(boundary_relation) > 0;
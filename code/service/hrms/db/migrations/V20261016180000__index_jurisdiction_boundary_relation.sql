@@ -0,0 +1,7 @@
+-- Containment lookups on the ancestor path (boundary_relation @> '["CODE"]') for
+-- employee search by boundary, including boundaries below the given one
+CREATE INDEX IF NOT EXISTS idx_jurisdiction_boundary_relation ON eg_hrms_jurisdiction_v3
+    USING GIN (boundary_relation jsonb_path_ops);
+
+CREATE INDEX IF NOT EXISTS idx_jurisdiction_employee_active ON eg_hrms_jurisdiction_v3 (employee_id)
+    WHERE is_active;
@@ -0,0 +1,19 @@
+-- Jurisdictions hold for a period. Replacing the jurisdictions of an employee closes the
+-- current records by setting valid_to instead of deleting them, so past ones stay queryable.
+
+ALTER TABLE eg_hrms_jurisdiction_v3
+    ADD COLUMN IF NOT EXISTS valid_from TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS valid_to TIMESTAMP WITH TIME ZONE;
+
+-- Existing jurisdictions hold from when they were created (created_time is in seconds)
+UPDATE eg_hrms_jurisdiction_v3
+SET valid_from = to_timestamp(created_time)
+WHERE valid_from IS NULL;
+
+ALTER TABLE eg_hrms_jurisdiction_v3
+    ALTER COLUMN valid_from SET NOT NULL,
+    ALTER COLUMN valid_from SET DEFAULT now(),
+    ADD CONSTRAINT chk_jurisdiction_validity CHECK (valid_to IS NULL OR valid_to > valid_from);
+
+CREATE INDEX IF NOT EXISTS idx_jurisdiction_employee_validity
+    ON eg_hrms_jurisdiction_v3 (employee_id, tenant_id, valid_from, valid_to);
@@ -0,0 +1,31 @@
+-- Append-only log of employee and jurisdiction changes. Each entry holds the changed
+-- fields with their old and new values and is written in the transaction of the change.
+
+CREATE TABLE IF NOT EXISTS eg_hrms_audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    entity_type VARCHAR(32) NOT NULL,
+    entity_id UUID NOT NULL,
+    -- No foreign key: the log outlives hard-deleted employees
+    employee_id UUID NOT NULL,
+    operation VARCHAR(16) NOT NULL,
+    changes JSONB NOT NULL,
+    actor VARCHAR(64) NOT NULL,
+    tenant_id VARCHAR(64) NOT NULL,
+    created_time BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_employee ON eg_hrms_audit_log (employee_id, tenant_id, id DESC);
+
+-- Serves the field filter, which matches entries whose changes contain the field as a key
+CREATE INDEX IF NOT EXISTS idx_audit_log_changes ON eg_hrms_audit_log USING GIN (changes);
+
+CREATE OR REPLACE FUNCTION eg_hrms_audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'eg_hrms_audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_audit_log_append_only ON eg_hrms_audit_log;
+CREATE TRIGGER trg_audit_log_append_only
+    BEFORE UPDATE OR DELETE ON eg_hrms_audit_log
+    FOR EACH ROW EXECUTE FUNCTION eg_hrms_audit_log_append_only();
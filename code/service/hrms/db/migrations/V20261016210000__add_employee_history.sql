@@ -0,0 +1,70 @@
+-- Versioned history of employees and jurisdictions, kept by triggers so that every write,
+-- including cascaded deletes, is captured. Each version holds the whole row as JSONB and
+-- the period it was current in; the state at a time t is the set of versions with
+-- valid_from <= t < valid_to. Rows are rebuilt with jsonb_populate_record against the live
+-- table type, so columns added later read as NULL in older versions.
+
+CREATE TABLE IF NOT EXISTS eg_hrms_employee_v3_history (
+    history_id BIGSERIAL PRIMARY KEY,
+    id UUID NOT NULL,
+    tenant_id VARCHAR(64) NOT NULL,
+    row_data JSONB NOT NULL,
+    valid_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    valid_to TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_employee_history_id ON eg_hrms_employee_v3_history (id, valid_from);
+CREATE INDEX IF NOT EXISTS idx_employee_history_tenant ON eg_hrms_employee_v3_history (tenant_id, valid_from);
+
+CREATE TABLE IF NOT EXISTS eg_hrms_jurisdiction_v3_history (
+    history_id BIGSERIAL PRIMARY KEY,
+    id UUID NOT NULL,
+    tenant_id VARCHAR(64) NOT NULL,
+    row_data JSONB NOT NULL,
+    valid_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    valid_to TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_jurisdiction_history_id ON eg_hrms_jurisdiction_v3_history (id, valid_from);
+CREATE INDEX IF NOT EXISTS idx_jurisdiction_history_employee
+    ON eg_hrms_jurisdiction_v3_history ((row_data->>'employee_id'), valid_from);
+
+-- Closes the current version of the changed row and, unless it was deleted, opens a new
+-- one. The history table is passed as the trigger argument. now() is the start of the
+-- transaction, so an employee and its jurisdictions written together share a timestamp
+-- and intermediate versions within one transaction get an empty period.
+CREATE OR REPLACE FUNCTION eg_hrms_record_history() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        EXECUTE format('UPDATE %I SET valid_to = $1 WHERE id = $2 AND valid_to IS NULL', TG_ARGV[0])
+            USING now(), OLD.id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        EXECUTE format('INSERT INTO %I (id, tenant_id, row_data, valid_from) VALUES ($1, $2, $3, $4)', TG_ARGV[0])
+            USING NEW.id, NEW.tenant_id, to_jsonb(NEW), now();
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_employee_history ON eg_hrms_employee_v3;
+CREATE TRIGGER trg_employee_history
+    AFTER INSERT OR UPDATE OR DELETE ON eg_hrms_employee_v3
+    FOR EACH ROW EXECUTE FUNCTION eg_hrms_record_history('eg_hrms_employee_v3_history');
+
+DROP TRIGGER IF EXISTS trg_jurisdiction_history ON eg_hrms_jurisdiction_v3;
+CREATE TRIGGER trg_jurisdiction_history
+    AFTER INSERT OR UPDATE OR DELETE ON eg_hrms_jurisdiction_v3
+    FOR EACH ROW EXECUTE FUNCTION eg_hrms_record_history('eg_hrms_jurisdiction_v3_history');
+
+-- Seed the current rows. Earlier versions were never kept, so each row is only known as
+-- of its last modification.
+INSERT INTO eg_hrms_employee_v3_history (id, tenant_id, row_data, valid_from)
+SELECT e.id, e.tenant_id, to_jsonb(e), to_timestamp(COALESCE(e.last_modified_time, e.created_time))
+FROM eg_hrms_employee_v3 e
+WHERE NOT EXISTS (SELECT 1 FROM eg_hrms_employee_v3_history h WHERE h.id = e.id);
+
+INSERT INTO eg_hrms_jurisdiction_v3_history (id, tenant_id, row_data, valid_from)
+SELECT j.id, j.tenant_id, to_jsonb(j), to_timestamp(COALESCE(j.last_modified_time, j.created_time))
+FROM eg_hrms_jurisdiction_v3 j
+WHERE NOT EXISTS (SELECT 1 FROM eg_hrms_jurisdiction_v3_history h WHERE h.id = j.id);
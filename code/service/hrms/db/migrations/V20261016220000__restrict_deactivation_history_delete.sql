@@ -0,0 +1,10 @@
+-- Status history is never deleted, so deleting an employee that has any is refused, as
+-- for assignments. Pending status actions are a work queue rather than history and still
+-- go with their employee.
+
+ALTER TABLE eg_hrms_deactivation_details
+    DROP CONSTRAINT IF EXISTS eg_hrms_deactivation_details_employee_id_fkey;
+
+ALTER TABLE eg_hrms_deactivation_details
+    ADD CONSTRAINT eg_hrms_deactivation_details_employee_id_fkey
+        FOREIGN KEY (employee_id) REFERENCES eg_hrms_employee_v3(id) ON DELETE RESTRICT;
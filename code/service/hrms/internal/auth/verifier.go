@@ -0,0 +1,295 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	// Register the hashes used by the supported algorithms
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+// MinSharedKeyLength is the shortest shared key accepted for HMAC signatures
+const MinSharedKeyLength = 32
+
+// Principal is the caller identified by a verified token
+type Principal struct {
+	UserID string
+	// Tenants lists the tenants the caller may act in. A tenant also covers its
+	// sub-tenants, so "pb" covers "pb.amritsar".
+	Tenants []string
+	Roles   []string
+}
+
+// HasTenant reports whether the caller may act in the tenant
+func (p *Principal) HasTenant(tenantID string) bool {
+	for _, t := range p.Tenants {
+		if t == tenantID || strings.HasPrefix(tenantID, t+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAnyRole reports whether the caller holds at least one of the roles
+func (p *Principal) HasAnyRole(roles ...string) bool {
+	for _, held := range p.Roles {
+		for _, role := range roles {
+			if held == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Config holds verifier configuration. At least one of SharedKey and JWKSFile is required.
+type Config struct {
+	// SharedKey verifies HS256, HS384 and HS512 signatures
+	SharedKey string
+	// JWKSFile is the path of a JSON Web Key Set holding the RSA and EC public keys that
+	// verify RS*, PS* and ES* signatures
+	JWKSFile string
+	// Issuer and Audience, when set, must match the iss and aud claims
+	Issuer   string
+	Audience string
+	// Leeway is the clock skew tolerated when checking exp and nbf
+	Leeway time.Duration
+}
+
+// Verifier checks JWT signatures and claims against locally configured keys
+type Verifier struct {
+	sharedKey []byte
+	keys      map[string]crypto.PublicKey
+	issuer    string
+	audience  string
+	leeway    time.Duration
+	now       func() time.Time
+}
+
+// NewVerifier creates a verifier from configuration, reading the JWKS file if one is given
+func NewVerifier(cfg Config) (*Verifier, error) {
+	if cfg.SharedKey == "" && cfg.JWKSFile == "" {
+		return nil, fmt.Errorf("a shared key or a JWKS file is required")
+	}
+	if cfg.SharedKey != "" && len(cfg.SharedKey) < MinSharedKeyLength {
+		return nil, fmt.Errorf("shared key must be at least %d bytes", MinSharedKeyLength)
+	}
+
+	v := &Verifier{
+		keys:     make(map[string]crypto.PublicKey),
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		leeway:   cfg.Leeway,
+		now:      time.Now,
+	}
+	if cfg.SharedKey != "" {
+		v.sharedKey = []byte(cfg.SharedKey)
+	}
+
+	if cfg.JWKSFile != "" {
+		data, err := os.ReadFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("read JWKS file: %w", err)
+		}
+		if v.keys, err = parseJWKS(data); err != nil {
+			return nil, err
+		}
+	}
+
+	return v, nil
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+}
+
+type claims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	ExpiresAt *int64   `json:"exp"`
+	NotBefore *int64   `json:"nbf"`
+	Tenants   []string `json:"tenants"`
+	Roles     []string `json:"roles"`
+}
+
+// audience accepts the aud claim as a single string or a list
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("aud must be a string or a list of strings")
+	}
+	*a = list
+	return nil
+}
+
+// Verify checks the signature, expiry and issuer and audience of a compact JWT and
+// returns the caller it identifies. Tokens without exp are rejected.
+func (v *Verifier) Verify(token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, fmt.Errorf("malformed token header: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %w", err)
+	}
+	if err := v.verifySignature(h, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	var c claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %w", err)
+	}
+	if err := v.checkClaims(&c); err != nil {
+		return nil, err
+	}
+
+	return &Principal{UserID: c.Subject, Tenants: c.Tenants, Roles: c.Roles}, nil
+}
+
+func (v *Verifier) checkClaims(c *claims) error {
+	now := v.now()
+	if c.ExpiresAt == nil {
+		return fmt.Errorf("token has no expiry")
+	}
+	if now.After(time.Unix(*c.ExpiresAt, 0).Add(v.leeway)) {
+		return fmt.Errorf("token has expired")
+	}
+	if c.NotBefore != nil && now.Before(time.Unix(*c.NotBefore, 0).Add(-v.leeway)) {
+		return fmt.Errorf("token is not valid yet")
+	}
+	if c.Subject == "" {
+		return fmt.Errorf("token has no subject")
+	}
+	if v.issuer != "" && c.Issuer != v.issuer {
+		return fmt.Errorf("unexpected token issuer %q", c.Issuer)
+	}
+	if v.audience != "" {
+		for _, aud := range c.Audience {
+			if aud == v.audience {
+				return nil
+			}
+		}
+		return fmt.Errorf("token is not meant for audience %q", v.audience)
+	}
+	return nil
+}
+
+// verifySignature checks the signature with the key the algorithm calls for. The
+// algorithm family must match the key type, so a public key is never used as an
+// HMAC secret.
+func (v *Verifier) verifySignature(h header, signed, signature []byte) error {
+	if len(h.Alg) != 5 {
+		return fmt.Errorf("unsupported signing algorithm %q", h.Alg)
+	}
+
+	var hash crypto.Hash
+	switch h.Alg[2:] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported signing algorithm %q", h.Alg)
+	}
+
+	if h.Alg[:2] == "HS" {
+		if v.sharedKey == nil {
+			return fmt.Errorf("signing algorithm %s is not accepted", h.Alg)
+		}
+		mac := hmac.New(hash.New, v.sharedKey)
+		mac.Write(signed)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return fmt.Errorf("invalid token signature")
+		}
+		return nil
+	}
+
+	key, err := v.publicKey(h.Kid)
+	if err != nil {
+		return err
+	}
+	hasher := hash.New()
+	hasher.Write(signed)
+	digest := hasher.Sum(nil)
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		switch h.Alg[:2] {
+		case "RS":
+			err = rsa.VerifyPKCS1v15(k, hash, digest, signature)
+		case "PS":
+			err = rsa.VerifyPSS(k, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		default:
+			return fmt.Errorf("signing algorithm %s does not match key %q", h.Alg, h.Kid)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid token signature")
+		}
+		return nil
+
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if h.Alg[:2] != "ES" || len(signature) != 2*size {
+			return fmt.Errorf("signing algorithm %s does not match key %q", h.Alg, h.Kid)
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			return fmt.Errorf("invalid token signature")
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unsupported key type for key %q", h.Kid)
+}
+
+// publicKey finds the JWKS key for a key ID. A token without kid may use the only key
+// of a single-key set.
+func (v *Verifier) publicKey(kid string) (crypto.PublicKey, error) {
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
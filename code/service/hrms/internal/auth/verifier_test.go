@@ -0,0 +1,276 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSharedKey = "0123456789abcdef0123456789abcdef"
+
+var testNow = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+func segment(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// signHS builds a token signed with an HMAC secret
+func signHS(t *testing.T, alg string, secret []byte, claims map[string]interface{}) string {
+	t.Helper()
+	signed := segment(t, map[string]string{"alg": alg, "typ": "JWT"}) + "." + segment(t, claims)
+	hash := map[string]crypto.Hash{"HS256": crypto.SHA256, "HS384": crypto.SHA384, "HS512": crypto.SHA512}[alg]
+	mac := hmac.New(hash.New, secret)
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// signRS builds an RS256 token signed with an RSA key
+func signRS(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	t.Helper()
+	signed := segment(t, map[string]string{"alg": "RS256", "kid": kid}) + "." + segment(t, claims)
+	digest := crypto.SHA256.New()
+	digest.Write([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest.Sum(nil))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// signES builds an ES256 token signed with a P-256 key
+func signES(t *testing.T, key *ecdsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	t.Helper()
+	signed := segment(t, map[string]string{"alg": "ES256", "kid": kid}) + "." + segment(t, claims)
+	digest := crypto.SHA256.New()
+	digest.Write([]byte(signed))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest.Sum(nil))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func b64(i *big.Int) string { return base64.RawURLEncoding.EncodeToString(i.Bytes()) }
+
+// writeJWKS writes a key set holding the public halves of the keys and returns its path
+func writeJWKS(t *testing.T, rsaKey *rsa.PrivateKey, ecKey *ecdsa.PrivateKey) string {
+	t.Helper()
+	set := map[string]interface{}{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": b64(rsaKey.N), "e": b64(big.NewInt(int64(rsaKey.E)))},
+		{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": b64(ecKey.X), "y": b64(ecKey.Y)},
+		{"kty": "RSA", "kid": "enc-1", "use": "enc", "n": b64(rsaKey.N), "e": b64(big.NewInt(int64(rsaKey.E)))},
+	}}
+	data, _ := json.Marshal(set)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write JWKS: %v", err)
+	}
+	return path
+}
+
+func TestVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate RSA key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate EC key: %v", err)
+	}
+	otherRSA, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate RSA key: %v", err)
+	}
+
+	v, err := NewVerifier(Config{
+		SharedKey: testSharedKey,
+		JWKSFile:  writeJWKS(t, rsaKey, ecKey),
+		Issuer:    "https://auth.example.org",
+		Audience:  "hrms",
+		Leeway:    time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	v.now = func() time.Time { return testNow }
+
+	claims := func(overrides map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{
+			"sub":     "user-1",
+			"iss":     "https://auth.example.org",
+			"aud":     "hrms",
+			"exp":     testNow.Add(time.Hour).Unix(),
+			"tenants": []string{"pg"},
+			"roles":   []string{"HRMS_ADMIN"},
+		}
+		for k, val := range overrides {
+			if val == nil {
+				delete(c, k)
+			} else {
+				c[k] = val
+			}
+		}
+		return c
+	}
+
+	rsaModulus := rsaKey.PublicKey.N.Bytes()
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{name: "HS256", token: signHS(t, "HS256", []byte(testSharedKey), claims(nil))},
+		{name: "HS512", token: signHS(t, "HS512", []byte(testSharedKey), claims(nil))},
+		{name: "RS256 from JWKS", token: signRS(t, rsaKey, "rsa-1", claims(nil))},
+		{name: "ES256 from JWKS", token: signES(t, ecKey, "ec-1", claims(nil))},
+		{name: "audience list", token: signHS(t, "HS256", []byte(testSharedKey), claims(map[string]interface{}{"aud": []string{"other", "hrms"}}))},
+		{name: "expired within leeway", token: signHS(t, "HS256", []byte(testSharedKey), claims(map[string]interface{}{"exp": testNow.Add(-30 * time.Second).Unix()}))},
+
+		{name: "wrong shared key", token: signHS(t, "HS256", []byte("fedcba9876543210fedcba9876543210"), claims(nil)), wantErr: "invalid token signature"},
+		{name: "RS256 by another key", token: signRS(t, otherRSA, "rsa-1", claims(nil)), wantErr: "invalid token signature"},
+		{name: "RSA key used with ES algorithm", token: signES(t, ecKey, "rsa-1", claims(nil)), wantErr: "does not match key"},
+		{name: "public key used as HMAC secret", token: signHS(t, "HS256", rsaModulus, claims(nil)), wantErr: "invalid token signature"},
+		{name: "encryption key not accepted", token: signRS(t, rsaKey, "enc-1", claims(nil)), wantErr: "unknown signing key"},
+		{name: "unknown kid", token: signRS(t, rsaKey, "rsa-9", claims(nil)), wantErr: "unknown signing key"},
+		{name: "alg none", token: segment(t, map[string]string{"alg": "none"}) + "." + segment(t, claims(nil)) + ".", wantErr: "unsupported signing algorithm"},
+		{name: "expired", token: signHS(t, "HS256", []byte(testSharedKey), claims(map[string]interface{}{"exp": testNow.Add(-2 * time.Minute).Unix()})), wantErr: "expired"},
+		{name: "no expiry", token: signHS(t, "HS256", []byte(testSharedKey), claims(map[string]interface{}{"exp": nil})), wantErr: "no expiry"},
+		{name: "not valid yet", token: signHS(t, "HS256", []byte(testSharedKey), claims(map[string]interface{}{"nbf": testNow.Add(5 * time.Minute).Unix()})), wantErr: "not valid yet"},
+		{name: "no subject", token: signHS(t, "HS256", []byte(testSharedKey), claims(map[string]interface{}{"sub": nil})), wantErr: "no subject"},
+		{name: "wrong issuer", token: signHS(t, "HS256", []byte(testSharedKey), claims(map[string]interface{}{"iss": "https://evil.example.org"})), wantErr: "unexpected token issuer"},
+		{name: "wrong audience", token: signHS(t, "HS256", []byte(testSharedKey), claims(map[string]interface{}{"aud": "billing"})), wantErr: "not meant for audience"},
+		{name: "two segments", token: "a.b", wantErr: "malformed token"},
+		{name: "bad signature encoding", token: signHS(t, "HS256", []byte(testSharedKey), claims(nil)) + "!", wantErr: "malformed token signature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := v.Verify(tt.token)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Verify error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if principal.UserID != "user-1" || !principal.HasTenant("pg") || !principal.HasAnyRole("HRMS_ADMIN") {
+				t.Fatalf("principal = %+v", principal)
+			}
+		})
+	}
+}
+
+func TestHMACRejectedWithoutSharedKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate RSA key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate EC key: %v", err)
+	}
+	v, err := NewVerifier(Config{JWKSFile: writeJWKS(t, rsaKey, ecKey)})
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+
+	token := signHS(t, "HS256", []byte(testSharedKey), map[string]interface{}{"sub": "user-1", "exp": time.Now().Add(time.Hour).Unix()})
+	if _, err := v.Verify(token); err == nil || !strings.Contains(err.Error(), "is not accepted") {
+		t.Fatalf("Verify error = %v, want HS256 rejected", err)
+	}
+}
+
+func TestNewVerifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{name: "shared key", cfg: Config{SharedKey: testSharedKey}},
+		{name: "no keys", cfg: Config{}, wantErr: "shared key or a JWKS file is required"},
+		{name: "short shared key", cfg: Config{SharedKey: "short"}, wantErr: "at least 32 bytes"},
+		{name: "missing JWKS file", cfg: Config{JWKSFile: filepath.Join(t.TempDir(), "missing.json")}, wantErr: "read JWKS file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewVerifier(tt.cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrincipalHasTenant(t *testing.T) {
+	p := &Principal{Tenants: []string{"pb", "pg.citya"}}
+
+	tests := []struct {
+		tenantID string
+		want     bool
+	}{
+		{tenantID: "pb", want: true},
+		{tenantID: "pb.amritsar", want: true},
+		{tenantID: "pg.citya", want: true},
+		{tenantID: "pg", want: false},
+		{tenantID: "pg.cityb", want: false},
+		{tenantID: "pbx", want: false},
+		{tenantID: "", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := p.HasTenant(tt.tenantID); got != tt.want {
+			t.Errorf("HasTenant(%q) = %v, want %v", tt.tenantID, got, tt.want)
+		}
+	}
+}
+
+func TestPrincipalHasAnyRole(t *testing.T) {
+	tests := []struct {
+		name  string
+		held  []string
+		roles []string
+		want  bool
+	}{
+		{name: "one of the roles", held: []string{"EMPLOYEE", "SUPERUSER"}, roles: []string{"HRMS_ADMIN", "SUPERUSER"}, want: true},
+		{name: "none of the roles", held: []string{"EMPLOYEE"}, roles: []string{"HRMS_ADMIN", "SUPERUSER"}, want: false},
+		{name: "no roles held", held: nil, roles: []string{"HRMS_ADMIN"}, want: false},
+		{name: "role names are case-sensitive", held: []string{"hrms_admin"}, roles: []string{"HRMS_ADMIN"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (&Principal{Roles: tt.held}).HasAnyRole(tt.roles...); got != tt.want {
+				t.Fatalf("HasAnyRole = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
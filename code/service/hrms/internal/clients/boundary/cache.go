@@ -0,0 +1,93 @@
+package boundary
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// cachingClient remembers boundary lookups per tenant and code for a fixed time, so
+// validating the same codes again does not call the boundary service. Codes the service
+// did not return are cached too, which means a newly created boundary is accepted only
+// after the TTL has passed.
+type cachingClient struct {
+	inner Client
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu      sync.Mutex
+	entries map[cacheKey]cacheEntry
+}
+
+type cacheKey struct {
+	tenantID string
+	code     string
+}
+
+type cacheEntry struct {
+	boundary  *Boundary // nil when the code does not exist
+	expiresAt time.Time
+}
+
+// NewCachingClient wraps a client with a TTL cache. A zero or negative ttl disables caching.
+func NewCachingClient(inner Client, ttl time.Duration) Client {
+	if ttl <= 0 {
+		return inner
+	}
+	return &cachingClient{
+		inner:   inner,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[cacheKey]cacheEntry),
+	}
+}
+
+// SearchByCodes answers from the cache where possible and asks the wrapped client for the rest
+func (c *cachingClient) SearchByCodes(ctx context.Context, tenantID string, codes []string) ([]Boundary, error) {
+	now := c.now()
+	result := make([]Boundary, 0, len(codes))
+	var missing []string
+
+	c.mu.Lock()
+	for _, code := range codes {
+		entry, ok := c.entries[cacheKey{tenantID, code}]
+		if !ok || now.After(entry.expiresAt) {
+			missing = append(missing, code)
+			continue
+		}
+		if entry.boundary != nil {
+			result = append(result, *entry.boundary)
+		}
+	}
+	c.mu.Unlock()
+
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	found, err := c.inner.SearchByCodes(ctx, tenantID, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := now.Add(c.ttl)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Drop expired entries so the cache does not grow without bound
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	for _, code := range missing {
+		c.entries[cacheKey{tenantID, code}] = cacheEntry{expiresAt: expiresAt}
+	}
+	for i := range found {
+		b := found[i]
+		c.entries[cacheKey{tenantID, b.Code}] = cacheEntry{boundary: &b, expiresAt: expiresAt}
+		result = append(result, b)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,171 @@
+package boundary
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+)
+
+// fakeClient serves boundaries by tenant and code and records the codes it was asked for
+type fakeClient struct {
+	boundaries map[string][]Boundary
+	fail       bool
+	asked      [][]string
+}
+
+func (c *fakeClient) SearchByCodes(ctx context.Context, tenantID string, codes []string) ([]Boundary, error) {
+	c.asked = append(c.asked, codes)
+	if c.fail {
+		return nil, stderrors.New("connection refused")
+	}
+	want := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		want[code] = true
+	}
+	var found []Boundary
+	for _, b := range c.boundaries[tenantID] {
+		if want[b.Code] {
+			found = append(found, b)
+		}
+	}
+	return found, nil
+}
+
+// cacheStep is one lookup through the cache, after moving the clock forward by advance
+type cacheStep struct {
+	advance  time.Duration
+	tenantID string
+	codes    []string
+	// fail makes the wrapped client fail this lookup
+	fail bool
+	// want is the codes returned, sorted; wantAsked the codes passed on to the wrapped
+	// client, or nil when it was not called
+	want      []string
+	wantAsked []string
+	wantErr   bool
+}
+
+func TestCachingClient(t *testing.T) {
+	const (
+		ttl    = time.Minute
+		tenant = "pg.citya"
+	)
+	boundaries := map[string][]Boundary{
+		tenant:     {{Code: "WARD-1"}, {Code: "WARD-2"}},
+		"pg.cityb": {{Code: "WARD-9"}},
+	}
+
+	tests := []struct {
+		name  string
+		steps []cacheStep
+	}{
+		{
+			name: "repeated lookup is served from the cache",
+			steps: []cacheStep{
+				{codes: []string{"WARD-1", "WARD-2"}, want: []string{"WARD-1", "WARD-2"}, wantAsked: []string{"WARD-1", "WARD-2"}},
+				{advance: ttl / 2, codes: []string{"WARD-2", "WARD-1"}, want: []string{"WARD-1", "WARD-2"}},
+			},
+		},
+		{
+			name: "only codes not cached are looked up",
+			steps: []cacheStep{
+				{codes: []string{"WARD-1"}, want: []string{"WARD-1"}, wantAsked: []string{"WARD-1"}},
+				{codes: []string{"WARD-1", "WARD-2"}, want: []string{"WARD-1", "WARD-2"}, wantAsked: []string{"WARD-2"}},
+			},
+		},
+		{
+			name: "entries are looked up again after the ttl",
+			steps: []cacheStep{
+				{codes: []string{"WARD-1"}, want: []string{"WARD-1"}, wantAsked: []string{"WARD-1"}},
+				{advance: ttl, codes: []string{"WARD-1"}, want: []string{"WARD-1"}},
+				{advance: time.Second, codes: []string{"WARD-1"}, want: []string{"WARD-1"}, wantAsked: []string{"WARD-1"}},
+			},
+		},
+		{
+			name: "unknown codes are cached as missing",
+			steps: []cacheStep{
+				{codes: []string{"WARD-404"}, want: []string{}, wantAsked: []string{"WARD-404"}},
+				{advance: ttl / 2, codes: []string{"WARD-404", "WARD-1"}, want: []string{"WARD-1"}, wantAsked: []string{"WARD-1"}},
+				{codes: []string{"WARD-404"}, want: []string{}},
+			},
+		},
+		{
+			name: "missing codes are looked up again after the ttl",
+			steps: []cacheStep{
+				{codes: []string{"WARD-404"}, want: []string{}, wantAsked: []string{"WARD-404"}},
+				{advance: ttl + time.Second, codes: []string{"WARD-404"}, want: []string{}, wantAsked: []string{"WARD-404"}},
+			},
+		},
+		{
+			name: "entries are kept per tenant",
+			steps: []cacheStep{
+				{codes: []string{"WARD-9"}, want: []string{}, wantAsked: []string{"WARD-9"}},
+				{tenantID: "pg.cityb", codes: []string{"WARD-9"}, want: []string{"WARD-9"}, wantAsked: []string{"WARD-9"}},
+				{codes: []string{"WARD-9"}, want: []string{}},
+			},
+		},
+		{
+			name: "failures are not cached",
+			steps: []cacheStep{
+				{codes: []string{"WARD-1"}, fail: true, wantAsked: []string{"WARD-1"}, wantErr: true},
+				{codes: []string{"WARD-1"}, want: []string{"WARD-1"}, wantAsked: []string{"WARD-1"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &fakeClient{boundaries: boundaries}
+			now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+			c := NewCachingClient(inner, ttl).(*cachingClient)
+			c.now = func() time.Time { return now }
+
+			for i, step := range tt.steps {
+				now = now.Add(step.advance)
+				inner.fail = step.fail
+				inner.asked = nil
+				tenantID := step.tenantID
+				if tenantID == "" {
+					tenantID = tenant
+				}
+
+				found, err := c.SearchByCodes(context.Background(), tenantID, step.codes)
+				if (err != nil) != step.wantErr {
+					t.Fatalf("step %d: error = %v, want error %v", i, err, step.wantErr)
+				}
+
+				var asked []string
+				if len(inner.asked) > 0 {
+					asked = inner.asked[0]
+				}
+				if len(inner.asked) > 1 || fmt.Sprint(asked) != fmt.Sprint(step.wantAsked) {
+					t.Fatalf("step %d: wrapped client asked for %v, want %v", i, inner.asked, step.wantAsked)
+				}
+				if step.wantErr {
+					continue
+				}
+
+				codes := make([]string, 0, len(found))
+				for _, b := range found {
+					codes = append(codes, b.Code)
+				}
+				sort.Strings(codes)
+				if fmt.Sprint(codes) != fmt.Sprint(step.want) {
+					t.Fatalf("step %d: got %v, want %v", i, codes, step.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewCachingClientWithoutTTL(t *testing.T) {
+	inner := &fakeClient{}
+	for _, ttl := range []time.Duration{0, -time.Minute} {
+		if c := NewCachingClient(inner, ttl); c != Client(inner) {
+			t.Fatalf("ttl %v: got %T, want the wrapped client", ttl, c)
+		}
+	}
+}
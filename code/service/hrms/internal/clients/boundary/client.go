@@ -10,25 +10,34 @@ import (
 	"strings"
 )
 
-type Client struct {
+// Client handles boundary service integration
+type Client interface {
+	// SearchByCodes returns the boundaries that exist among the given codes
+	SearchByCodes(ctx context.Context, tenantID string, codes []string) ([]Boundary, error)
+}
+
+type client struct {
 	httpClient *http.Client
 	baseURL    string
 }
 
 type Boundary struct {
-	Code string `json:"code"`
-	Name string `json:"name"`
-	// Add other fields as needed
+	Code          string `json:"code"`
+	Name          string `json:"name"`
+	BoundaryType  string `json:"boundaryType"`
+	HierarchyType string `json:"hierarchyType"`
+	// Parent is the code of the enclosing boundary; empty for the root of a hierarchy
+	Parent string `json:"parent,omitempty"`
 }
 
-func NewClient(baseURL string) *Client {
-	return &Client{
+func NewClient(baseURL string) Client {
+	return &client{
 		httpClient: &http.Client{},
 		baseURL:    strings.TrimSuffix(baseURL, "/"),
 	}
 }
 
-func (c *Client) SearchByCodes(ctx context.Context, tenantID string, codes []string) ([]Boundary, error) {
+func (c *client) SearchByCodes(ctx context.Context, tenantID string, codes []string) ([]Boundary, error) {
 	if len(codes) == 0 {
 		return []Boundary{}, nil
 	}
@@ -0,0 +1,162 @@
+package individual
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when the Individual service has no record with the requested ID
+var ErrNotFound = errors.New("individual not found")
+
+// Client handles Individual service integration. Individuals are the person records
+// that employees are linked to through Employee.IndividualID.
+type Client interface {
+	// Create creates a new Individual and returns it with its ID set
+	Create(ctx context.Context, tenantID string, individual *Individual) (*Individual, error)
+
+	// GetByID returns the Individual with the given ID, or ErrNotFound
+	GetByID(ctx context.Context, tenantID, id string) (*Individual, error)
+
+	// GetByIDs returns the Individuals with the given IDs in one request. IDs without a
+	// record are left out of the result.
+	GetByIDs(ctx context.Context, tenantID string, ids []string) ([]*Individual, error)
+
+	// SearchByMobileNumber returns the Individuals registered with a mobile number
+	SearchByMobileNumber(ctx context.Context, tenantID, mobileNumber string) ([]*Individual, error)
+}
+
+// Individual is a person record held by the Individual service
+type Individual struct {
+	ID           string     `json:"id,omitempty"`
+	TenantID     string     `json:"tenantId,omitempty"`
+	Name         Name       `json:"name"`
+	MobileNumber string     `json:"mobileNumber,omitempty"`
+	Email        string     `json:"email,omitempty"`
+	Gender       string     `json:"gender,omitempty"`
+	DateOfBirth  *time.Time `json:"dateOfBirth,omitempty"`
+}
+
+// Name is the name of an Individual
+type Name struct {
+	GivenName  string `json:"givenName"`
+	FamilyName string `json:"familyName,omitempty"`
+	OtherNames string `json:"otherNames,omitempty"`
+}
+
+type client struct {
+	httpClient *http.Client
+	host       string
+	path       string
+}
+
+// Config holds Individual client configuration
+type Config struct {
+	Host    string
+	Path    string
+	Timeout time.Duration
+}
+
+// NewClient creates a new Individual client
+func NewClient(cfg Config) Client {
+	return &client{
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+		host:       strings.TrimSuffix(cfg.Host, "/"),
+		path:       cfg.Path,
+	}
+}
+
+// Create creates a new Individual
+func (c *client) Create(ctx context.Context, tenantID string, individual *Individual) (*Individual, error) {
+	jsonData, err := json.Marshal(individual)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal individual request: %w", err)
+	}
+
+	var created Individual
+	if err := c.do(ctx, http.MethodPost, c.host+c.path, tenantID, jsonData, &created); err != nil {
+		return nil, err
+	}
+	if created.ID == "" {
+		return nil, fmt.Errorf("individual service response missing 'id'")
+	}
+	return &created, nil
+}
+
+// GetByID returns the Individual with the given ID
+func (c *client) GetByID(ctx context.Context, tenantID, id string) (*Individual, error) {
+	var individual Individual
+	if err := c.do(ctx, http.MethodGet, c.host+c.path+"/"+url.PathEscape(id), tenantID, nil, &individual); err != nil {
+		return nil, err
+	}
+	return &individual, nil
+}
+
+// GetByIDs returns the Individuals with the given IDs
+func (c *client) GetByIDs(ctx context.Context, tenantID string, ids []string) ([]*Individual, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	reqURL := fmt.Sprintf("%s%s?ids=%s", c.host, c.path, url.QueryEscape(strings.Join(ids, ",")))
+
+	var individuals []*Individual
+	if err := c.do(ctx, http.MethodGet, reqURL, tenantID, nil, &individuals); err != nil {
+		return nil, err
+	}
+	return individuals, nil
+}
+
+// SearchByMobileNumber returns the Individuals registered with a mobile number
+func (c *client) SearchByMobileNumber(ctx context.Context, tenantID, mobileNumber string) ([]*Individual, error) {
+	reqURL := fmt.Sprintf("%s%s?mobileNumber=%s", c.host, c.path, url.QueryEscape(mobileNumber))
+
+	var individuals []*Individual
+	if err := c.do(ctx, http.MethodGet, reqURL, tenantID, nil, &individuals); err != nil {
+		return nil, err
+	}
+	return individuals, nil
+}
+
+// do sends a request to the Individual service and decodes the JSON response into out
+func (c *client) do(ctx context.Context, method, reqURL, tenantID string, body []byte, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if tenantID != "" {
+		req.Header.Set("X-Tenant-ID", tenantID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("individual service request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("individual service returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode individual service response: %w", err)
+	}
+	return nil
+}
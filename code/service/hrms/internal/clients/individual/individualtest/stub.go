@@ -0,0 +1,116 @@
+// Package individualtest provides an in-memory Individual service for tests
+package individualtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"hrms/internal/clients/individual"
+)
+
+// StubServer is an in-memory stand-in for the Individual service. It serves the
+// endpoints used by individual.Client under the given path.
+type StubServer struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	individuals map[string]*individual.Individual
+}
+
+// NewStubServer starts a stub Individual service. Callers must Close it.
+// Point a client at it with individual.Config{Host: stub.URL, Path: path}.
+func NewStubServer(path string) *StubServer {
+	s := &StubServer{individuals: make(map[string]*individual.Individual)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, s.handleCollection)
+	mux.HandleFunc(strings.TrimSuffix(path, "/")+"/", s.handleItem)
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// Add stores an Individual directly, assigning an ID if it has none, and returns it
+func (s *StubServer) Add(ind *individual.Individual) *individual.Individual {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ind.ID == "" {
+		ind.ID = uuid.New().String()
+	}
+	s.individuals[ind.ID] = ind
+	return ind
+}
+
+// handleCollection serves create (POST) and search by IDs or mobile number (GET)
+func (s *StubServer) handleCollection(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Header.Get("X-Tenant-ID")
+
+	switch r.Method {
+	case http.MethodPost:
+		var ind individual.Individual
+		if err := json.NewDecoder(r.Body).Decode(&ind); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ind.ID = ""
+		ind.TenantID = tenantID
+		writeJSON(w, http.StatusCreated, s.Add(&ind))
+
+	case http.MethodGet:
+		query := r.URL.Query()
+
+		s.mu.Lock()
+		matches := make([]*individual.Individual, 0)
+		if ids := query.Get("ids"); ids != "" {
+			for _, id := range strings.Split(ids, ",") {
+				if ind, ok := s.individuals[id]; ok && ind.TenantID == tenantID {
+					matches = append(matches, ind)
+				}
+			}
+		} else {
+			mobileNumber := query.Get("mobileNumber")
+			for _, ind := range s.individuals {
+				if ind.TenantID == tenantID && ind.MobileNumber == mobileNumber {
+					matches = append(matches, ind)
+				}
+			}
+		}
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, matches)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// handleItem serves lookup by ID
+func (s *StubServer) handleItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+	s.mu.Lock()
+	ind, ok := s.individuals[id]
+	s.mu.Unlock()
+
+	if !ok || ind.TenantID != r.Header.Get("X-Tenant-ID") {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, http.StatusOK, ind)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
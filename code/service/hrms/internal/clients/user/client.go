@@ -0,0 +1,142 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when the user service has no account with the requested ID
+var ErrNotFound = errors.New("user not found")
+
+// TypeEmployee is the user type of employee login accounts
+const TypeEmployee = "EMPLOYEE"
+
+// Client handles user service integration for employee login accounts
+type Client interface {
+	// CreateUser creates a login account and returns it with its ID set
+	CreateUser(ctx context.Context, tenantID string, user *User) (*User, error)
+
+	// SetActive enables or disables a login account
+	SetActive(ctx context.Context, tenantID, userID string, active bool) error
+
+	// DeleteUser removes a login account. It is used to undo CreateUser.
+	DeleteUser(ctx context.Context, tenantID, userID string) error
+}
+
+// User is a login account held by the user service
+type User struct {
+	ID           string `json:"id,omitempty"`
+	TenantID     string `json:"tenantId,omitempty"`
+	UserName     string `json:"userName"`
+	Password     string `json:"password,omitempty"`
+	Name         string `json:"name,omitempty"`
+	MobileNumber string `json:"mobileNumber,omitempty"`
+	Email        string `json:"emailId,omitempty"`
+	Type         string `json:"type"`
+	Active       bool   `json:"active"`
+}
+
+type client struct {
+	httpClient *http.Client
+	host       string
+	path       string
+}
+
+// Config holds user client configuration
+type Config struct {
+	Host    string
+	Path    string
+	Timeout time.Duration
+}
+
+// NewClient creates a new user client
+func NewClient(cfg Config) Client {
+	return &client{
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+		host:       strings.TrimSuffix(cfg.Host, "/"),
+		path:       cfg.Path,
+	}
+}
+
+// CreateUser creates a login account
+func (c *client) CreateUser(ctx context.Context, tenantID string, user *User) (*User, error) {
+	jsonData, err := json.Marshal(user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal user request: %w", err)
+	}
+
+	var created User
+	if err := c.do(ctx, http.MethodPost, c.host+c.path, tenantID, jsonData, &created); err != nil {
+		return nil, err
+	}
+	if created.ID == "" {
+		return nil, fmt.Errorf("user service response missing 'id'")
+	}
+	return &created, nil
+}
+
+// SetActive enables or disables a login account
+func (c *client) SetActive(ctx context.Context, tenantID, userID string, active bool) error {
+	jsonData, err := json.Marshal(map[string]bool{"active": active})
+	if err != nil {
+		return fmt.Errorf("failed to marshal user request: %w", err)
+	}
+	return c.do(ctx, http.MethodPatch, c.userURL(userID), tenantID, jsonData, nil)
+}
+
+// DeleteUser removes a login account
+func (c *client) DeleteUser(ctx context.Context, tenantID, userID string) error {
+	return c.do(ctx, http.MethodDelete, c.userURL(userID), tenantID, nil, nil)
+}
+
+func (c *client) userURL(userID string) string {
+	return c.host + c.path + "/" + url.PathEscape(userID)
+}
+
+// do sends a request to the user service and decodes the JSON response into out when out is not nil
+func (c *client) do(ctx context.Context, method, reqURL, tenantID string, body []byte, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if tenantID != "" {
+		req.Header.Set("X-Tenant-ID", tenantID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("user service request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("user service returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode user service response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package user
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// MinPasswordLength is the shortest password the generator produces
+const MinPasswordLength = 8
+
+// Character classes every generated password draws at least one character from
+const (
+	upperChars   = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	lowerChars   = "abcdefghijkmnopqrstuvwxyz"
+	digitChars   = "23456789"
+	specialChars = "@#$%&*!"
+)
+
+// PasswordGenerator generates initial passwords for new login accounts.
+// The length must be kept in sync with the user service password policy.
+type PasswordGenerator struct {
+	length int
+}
+
+// NewPasswordGenerator creates a generator for passwords of the given length
+func NewPasswordGenerator(length int) (*PasswordGenerator, error) {
+	if length < MinPasswordLength {
+		return nil, fmt.Errorf("password length must be at least %d, got %d", MinPasswordLength, length)
+	}
+	return &PasswordGenerator{length: length}, nil
+}
+
+// Generate returns a random password with at least one upper case letter, lower case
+// letter, digit and special character. Look-alike characters such as 0/O and 1/l are left out.
+func (g *PasswordGenerator) Generate() (string, error) {
+	classes := []string{upperChars, lowerChars, digitChars, specialChars}
+	all := upperChars + lowerChars + digitChars + specialChars
+
+	password := make([]byte, 0, g.length)
+	for _, class := range classes {
+		c, err := randomChar(class)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+	for len(password) < g.length {
+		c, err := randomChar(all)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+
+	// Shuffle so the required classes are not always at the start
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password: %w", err)
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+
+	return string(password), nil
+}
+
+func randomChar(chars string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate password: %w", err)
+	}
+	return chars[n.Int64()], nil
+}
@@ -7,13 +7,27 @@ import (
 	"strings"
 )
 
+// Development PII keys. They are published in this repository, so they are only used in
+// DEV_MODE and must never protect real data.
+const (
+	devEncryptionKey = "ZGV2LW9ubHktcGlpLWtleS1jaGFuZ2UtbWUtMTIzNDU="
+	devHashKey       = "dev-only-pii-hash-key"
+)
+
 // Config holds all configuration for the application
 type Config struct {
+	// DevMode allows development defaults that are unsafe in production
+	DevMode    bool
 	Server     ServerConfig
 	Database   DatabaseConfig
 	IDGen      IDGenConfig
 	Boundary   BoundaryConfig
 	Individual IndividualConfig
+	User       UserConfig
+	Scheduler  SchedulerConfig
+	PII        PIIConfig
+	Auth       AuthConfig
+	Search     SearchConfig
 }
 
 // ServerConfig holds server-related configuration
@@ -40,14 +54,75 @@ type IDGenConfig struct {
 	IDGenName string `mapstructure:"idgen_name"`
 }
 
+// Boundary validation modes
+const (
+	// BoundaryValidationStrict rejects jurisdiction writes when the boundary service is unavailable
+	BoundaryValidationStrict = "strict"
+	// BoundaryValidationLenient accepts boundary codes unchecked when the boundary service is unavailable
+	BoundaryValidationLenient = "lenient"
+)
+
 type BoundaryConfig struct {
-	BaseURL string `mapstructure:"base_url"`
+	BaseURL        string `mapstructure:"base_url"`
+	ValidationMode string `mapstructure:"validation_mode"`
+	// CacheTTLSeconds is how long boundary lookups are cached; 0 disables the cache
+	CacheTTLSeconds int `mapstructure:"cache_ttl_seconds"`
 }
 
+// IndividualConfig holds configuration for the Individual service client
 type IndividualConfig struct {
 	Host    string `mapstructure:"host"`
 	Path    string `mapstructure:"path"`
-	Timeout bool   `mapstructure:"timeout"`
+	Enabled bool   `mapstructure:"enabled"`
+	// Timeout is the request timeout in seconds
+	Timeout int `mapstructure:"timeout"`
+}
+
+// UserConfig holds configuration for the user service client that manages employee login accounts
+type UserConfig struct {
+	Host    string `mapstructure:"host"`
+	Path    string `mapstructure:"path"`
+	Enabled bool   `mapstructure:"enabled"`
+	// Timeout is the request timeout in seconds
+	Timeout int `mapstructure:"timeout"`
+	// DefaultPasswordLength is the length of generated passwords; keep it in sync with the user service password policy
+	DefaultPasswordLength int `mapstructure:"default_password_length"`
+}
+
+// SchedulerConfig holds configuration for the scheduled status change runner
+type SchedulerConfig struct {
+	Enabled         bool `mapstructure:"enabled"`
+	IntervalSeconds int  `mapstructure:"interval_seconds"`
+}
+
+// PIIConfig holds configuration for encrypting and masking personal data
+type PIIConfig struct {
+	// EncryptionKeys maps key IDs to base64 encoded AES keys
+	EncryptionKeys map[string]string `mapstructure:"encryption_keys"`
+	ActiveKeyID    string            `mapstructure:"active_key_id"`
+	HashKey        string            `mapstructure:"hash_key"`
+	// UnmaskRoles lists the roles allowed to see personal data unmasked
+	UnmaskRoles []string `mapstructure:"unmask_roles"`
+}
+
+// AuthConfig holds configuration for verifying the bearer tokens of requests
+type AuthConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// JWKSFile is the path of a JSON Web Key Set with the public keys that sign tokens
+	JWKSFile string `mapstructure:"jwks_file"`
+	// SharedKey is the HMAC secret for HS256/384/512 signed tokens
+	SharedKey string `mapstructure:"shared_key"`
+	Issuer    string `mapstructure:"issuer"`
+	Audience  string `mapstructure:"audience"`
+	// LeewaySeconds is the clock skew tolerated when checking token expiry
+	LeewaySeconds int `mapstructure:"leeway_seconds"`
+}
+
+// SearchConfig holds configuration for employee search
+type SearchConfig struct {
+	// OpenSearchRoles lists the roles allowed to search and count without uuids, codes or
+	// phone, and to resolve employees
+	OpenSearchRoles []string `mapstructure:"open_search_roles"`
 }
 
 // ServiceConfig holds configuration for an external service
@@ -63,6 +138,7 @@ type ServiceConfig struct {
 // LoadConfig loads configuration from environment variables with sensible defaults
 func LoadConfig() (*Config, error) {
 	cfg := &Config{
+		DevMode: getEnvAsBool("DEV_MODE", false),
 		Server: ServerConfig{
 			Port:        getEnv("SERVER_PORT", "8080"),
 			ContextPath: getEnv("SERVER_CONTEXT_PATH", "/hrms"),
@@ -82,13 +158,72 @@ func LoadConfig() (*Config, error) {
 			IDGenName: getEnv("IDGEN_NAME", "hrms.idgen"),
 		},
 		Boundary: BoundaryConfig{
-			BaseURL: getEnv("BOUNDARY_HOST", "http://localhost:8095"),
+			BaseURL:         getEnv("BOUNDARY_HOST", "http://localhost:8095"),
+			ValidationMode:  getEnv("BOUNDARY_VALIDATION_MODE", BoundaryValidationStrict),
+			CacheTTLSeconds: getEnvAsInt("BOUNDARY_CACHE_TTL_SECONDS", 300),
 		},
 		Individual: IndividualConfig{
 			Host:    getEnv("INDIVIDUAL_HOST", "http://localhost:8086"),
 			Path:    getEnv("INDIVIDUAL_PATH", "/individual/v1"),
-			Timeout: getEnvAsBool("INDIVIDUAL_TIMEOUT", true),
+			Enabled: getEnvAsBool("INDIVIDUAL_ENABLED", true),
+			Timeout: getEnvAsInt("INDIVIDUAL_TIMEOUT", 10),
+		},
+		User: UserConfig{
+			Host:                  getEnv("USER_SERVICE_HOST", "http://localhost:8081"),
+			Path:                  getEnv("USER_SERVICE_PATH", "/user/v1"),
+			Enabled:               getEnvAsBool("USER_SERVICE_ENABLED", true),
+			Timeout:               getEnvAsInt("USER_SERVICE_TIMEOUT", 10),
+			DefaultPasswordLength: getEnvAsInt("HRMS_DEFAULT_PWD_LENGTH", 10),
+		},
+		Scheduler: SchedulerConfig{
+			Enabled:         getEnvAsBool("STATUS_SCHEDULER_ENABLED", true),
+			IntervalSeconds: getEnvAsInt("STATUS_SCHEDULER_INTERVAL_SECONDS", 60),
+		},
+		PII: PIIConfig{
+			EncryptionKeys: getEnvAsMap("PII_ENCRYPTION_KEYS", nil),
+			ActiveKeyID:    getEnv("PII_ACTIVE_KEY_ID", ""),
+			HashKey:        getEnv("PII_HASH_KEY", ""),
+			UnmaskRoles:    getEnvAsSlice("PII_UNMASK_ROLES", []string{"HRMS_ADMIN", "SUPERUSER"}),
+		},
+		Auth: AuthConfig{
+			Enabled:       getEnvAsBool("AUTH_ENABLED", true),
+			JWKSFile:      getEnv("AUTH_JWKS_FILE", ""),
+			SharedKey:     getEnv("AUTH_SHARED_KEY", ""),
+			Issuer:        getEnv("AUTH_ISSUER", ""),
+			Audience:      getEnv("AUTH_AUDIENCE", ""),
+			LeewaySeconds: getEnvAsInt("AUTH_LEEWAY_SECONDS", 60),
 		},
+		Search: SearchConfig{
+			OpenSearchRoles: getEnvAsSlice("OPEN_SEARCH_ENABLED_ROLES", []string{"SUPERUSER", "ADMIN"}),
+		},
+	}
+
+	if cfg.Boundary.ValidationMode != BoundaryValidationStrict && cfg.Boundary.ValidationMode != BoundaryValidationLenient {
+		return nil, fmt.Errorf("invalid BOUNDARY_VALIDATION_MODE %q: must be %s or %s",
+			cfg.Boundary.ValidationMode, BoundaryValidationStrict, BoundaryValidationLenient)
+	}
+
+	if len(cfg.PII.EncryptionKeys) == 0 || cfg.PII.HashKey == "" {
+		if !cfg.DevMode {
+			return nil, fmt.Errorf("PII_ENCRYPTION_KEYS and PII_HASH_KEY are required unless DEV_MODE=true")
+		}
+		if len(cfg.PII.EncryptionKeys) == 0 {
+			cfg.PII.EncryptionKeys = map[string]string{"dev": devEncryptionKey}
+		}
+		if cfg.PII.HashKey == "" {
+			cfg.PII.HashKey = devHashKey
+		}
+	}
+
+	// A single configured key is the active one unless another is named
+	if cfg.PII.ActiveKeyID == "" && len(cfg.PII.EncryptionKeys) == 1 {
+		for id := range cfg.PII.EncryptionKeys {
+			cfg.PII.ActiveKeyID = id
+		}
+	}
+
+	if cfg.Auth.Enabled && cfg.Auth.JWKSFile == "" && cfg.Auth.SharedKey == "" {
+		return nil, fmt.Errorf("AUTH_JWKS_FILE or AUTH_SHARED_KEY is required unless AUTH_ENABLED=false")
 	}
 
 	return cfg, nil
@@ -141,3 +276,21 @@ func getEnvAsSlice(key string, defaultVal []string) []string {
 	}
 	return defaultVal
 }
+
+// getEnvAsMap parses a comma separated list of key=value pairs
+func getEnvAsMap(key string, defaultVal map[string]string) map[string]string {
+	pairs := getEnvAsSlice(key, nil)
+	if len(pairs) == 0 {
+		return defaultVal
+	}
+
+	out := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		out[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return out
+}
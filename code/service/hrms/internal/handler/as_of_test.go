@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"hrms/internal/middleware"
+	"hrms/internal/models"
+	"hrms/internal/service"
+)
+
+// asOfService records the time each read was made as of; a zero time is a current read
+type asOfService struct {
+	service.EmployeeService
+	readAt *time.Time
+	called bool
+}
+
+func (s *asOfService) GetEmployeeByUUID(ctx context.Context, uuid, tenantID string) (*models.EmployeeResponse, error) {
+	s.called = true
+	return &models.EmployeeResponse{ID: uuid}, nil
+}
+
+func (s *asOfService) GetEmployeeAsOf(ctx context.Context, uuid, tenantID string, asOf time.Time) (*models.EmployeeResponse, error) {
+	s.called, s.readAt = true, &asOf
+	return &models.EmployeeResponse{ID: uuid}, nil
+}
+
+func (s *asOfService) SearchEmployees(ctx context.Context, criteria *models.EmployeeSearchCriteria) (*models.EmployeeSearchResponse, error) {
+	s.called, s.readAt = true, criteria.AsOf
+	return &models.EmployeeSearchResponse{}, nil
+}
+
+func TestAsOfParameter(t *testing.T) {
+	const id = "6f1c3a52-0c6c-4bb4-8a8e-3b1d8b0c2a11"
+	midnight := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	instant := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+		wantAsOf   *time.Time
+	}{
+		{name: "get without asOf reads current state", url: "/employees/" + id, wantStatus: http.StatusOK},
+		{name: "get as of a date", url: "/employees/" + id + "?asOf=2026-03-01", wantStatus: http.StatusOK, wantAsOf: &midnight},
+		{name: "get as of a timestamp", url: "/employees/" + id + "?asOf=2026-03-01T15:00:00%2B05:30", wantStatus: http.StatusOK, wantAsOf: &instant},
+		{name: "get with malformed asOf", url: "/employees/" + id + "?asOf=01-03-2026", wantStatus: http.StatusBadRequest},
+		{name: "search without asOf reads current state", url: "/employees?codes=EMP-1", wantStatus: http.StatusOK},
+		{name: "search as of a date", url: "/employees?codes=EMP-1&asOf=2026-03-01", wantStatus: http.StatusOK, wantAsOf: &midnight},
+		{name: "search with malformed asOf", url: "/employees?codes=EMP-1&asOf=yesterday", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &asOfService{}
+			h := NewEmployeeHandler(svc, logrus.New(), nil, nil)
+			logger := logrus.New()
+			logger.SetLevel(logrus.PanicLevel)
+
+			r := gin.New()
+			r.Use(middleware.ErrorHandler(logger), func(c *gin.Context) {
+				c.Set("tenantID", "pg.citya")
+				c.Next()
+			})
+			r.GET("/employees", h.SearchEmployees)
+			r.GET("/employees/:id", h.GetEmployeeByUUID)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body: %s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				if svc.called {
+					t.Fatalf("service was called for a rejected request")
+				}
+				return
+			}
+			switch {
+			case tt.wantAsOf == nil && svc.readAt != nil:
+				t.Fatalf("read as of %v, want a current read", svc.readAt)
+			case tt.wantAsOf != nil && (svc.readAt == nil || !svc.readAt.Equal(*tt.wantAsOf)):
+				t.Fatalf("read as of %v, want %v", svc.readAt, tt.wantAsOf)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package handler
 
 import (
 	"net/http"
+	"sort"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -13,160 +15,340 @@ import (
 )
 
 type EmployeeHandler struct {
-	service service.EmployeeService
-	logger  *logrus.Logger
+	service         service.EmployeeService
+	logger          *logrus.Logger
+	unmaskRoles     map[string]bool
+	openSearchRoles map[string]bool
 }
 
-func NewEmployeeHandler(service service.EmployeeService, logger *logrus.Logger) *EmployeeHandler {
-	return &EmployeeHandler{
-		service: service,
-		logger:  logger,
+// NewEmployeeHandler creates an employee handler. Personal details in responses are
+// masked unless the caller holds one of unmaskRoles. Searches and counts must name
+// employees by uuids, codes or phone, and resolution is refused, unless the caller holds
+// one of openSearchRoles; a nil openSearchRoles leaves them unrestricted, as when
+// authentication is disabled.
+func NewEmployeeHandler(service service.EmployeeService, logger *logrus.Logger, unmaskRoles, openSearchRoles []string) *EmployeeHandler {
+	h := &EmployeeHandler{
+		service:     service,
+		logger:      logger,
+		unmaskRoles: roleSet(unmaskRoles),
+	}
+	if openSearchRoles != nil {
+		h.openSearchRoles = roleSet(openSearchRoles)
+	}
+	return h
+}
+
+func roleSet(roles []string) map[string]bool {
+	set := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		set[role] = true
 	}
+	return set
 }
 
-func (h *EmployeeHandler) handleError(c *gin.Context, status int, err error) {
-	h.logger.WithError(err).Error("Request failed")
-	c.JSON(status, gin.H{"error": err.Error()})
+// hasAnyRole reports whether the caller holds one of the roles
+func hasAnyRole(c *gin.Context, roles map[string]bool) bool {
+	for _, role := range c.GetStringSlice("roles") {
+		if roles[role] {
+			return true
+		}
+	}
+	return false
+}
+
+// maskPII masks personal details of the employees unless the caller may see them
+func (h *EmployeeHandler) maskPII(c *gin.Context, employees ...*models.EmployeeResponse) {
+	if hasAnyRole(c, h.unmaskRoles) {
+		return
+	}
+	for _, employee := range employees {
+		employee.MaskPII()
+	}
+}
+
+// handleError attaches the error to the request; middleware.ErrorHandler renders it
+func (h *EmployeeHandler) handleError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
 }
 
 func (h *EmployeeHandler) CreateEmployees(c *gin.Context) {
 	tenantID, exists := c.Get("tenantID")
 	if !exists {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
 		return
 	}
 
 	var req []*models.CreateEmployeeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.handleError(c, http.StatusBadRequest, errors.New("INVALID_REQUEST", err.Error()))
+		h.handleError(c, errors.New("INVALID_REQUEST", err.Error()))
 		return
 	}
 
 	tID, ok := tenantID.(string)
 	if !ok {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
+		return
+	}
+
+	// In partial mode valid employees are created even if others fail
+	if c.Query("mode") == "partial" {
+		results := h.service.CreateEmployeesPartial(c.Request.Context(), req, tID)
+		for _, result := range results {
+			h.maskPII(c, result.Employee)
+		}
+		c.JSON(http.StatusMultiStatus, results)
 		return
 	}
 
 	employees, err := h.service.CreateEmployees(c.Request.Context(), req, tID)
 	if err != nil {
-		h.handleError(c, http.StatusInternalServerError, err)
+		h.handleError(c, err)
 		return
 	}
 
+	h.maskPII(c, employees...)
 	c.JSON(http.StatusCreated, employees)
 }
 
 func (h *EmployeeHandler) SearchEmployees(c *gin.Context) {
 	tenantID, exists := c.Get("tenantID")
 	if !exists {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
 		return
 	}
 
 	tID, ok := tenantID.(string)
 	if !ok {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
 		return
 	}
 
-	criteria := &models.EmployeeSearchCriteria{
-		TenantID: tID,
-		// Add other search criteria from query params as needed
+	criteria := &models.EmployeeSearchCriteria{}
+	if err := c.ShouldBindQuery(criteria); err != nil {
+		h.handleError(c, errors.New("INVALID_REQUEST", err.Error()))
+		return
+	}
+	criteria.TenantID = tID
+
+	asOf, err := parseTimeParam(c, "asOf")
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	criteria.AsOf = asOf
+
+	if err := h.checkOpenSearch(c, criteria, "SearchEmployees"); err != nil {
+		h.handleError(c, err)
+		return
 	}
 
 	employees, err := h.service.SearchEmployees(c.Request.Context(), criteria)
 	if err != nil {
-		h.handleError(c, http.StatusInternalServerError, err)
+		h.handleError(c, err)
 		return
 	}
 
+	h.maskPII(c, employees.Employees...)
 	c.JSON(http.StatusOK, employees)
 }
 
+// checkOpenSearch refuses searches and counts that do not name employees by uuids, codes
+// or phone unless the caller holds an open search role
+func (h *EmployeeHandler) checkOpenSearch(c *gin.Context, criteria *models.EmployeeSearchCriteria, op string) error {
+	if len(criteria.UUIDs) > 0 || len(criteria.Codes) > 0 || criteria.Phone != "" {
+		return nil
+	}
+	return h.requireOpenSearch(c, op)
+}
+
+// requireOpenSearch refuses tenant-wide employee listings unless the caller holds an open
+// search role
+func (h *EmployeeHandler) requireOpenSearch(c *gin.Context, op string) error {
+	if h.openSearchRoles == nil || hasAnyRole(c, h.openSearchRoles) {
+		return nil
+	}
+
+	roles := make([]string, 0, len(h.openSearchRoles))
+	for role := range h.openSearchRoles {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return errors.New("FORBIDDEN", "open search not allowed").
+		WithDescription("listing employees without uuids, codes or phone requires one of the roles " + strings.Join(roles, ", ")).
+		WithOperation(op)
+}
+
+// ResolveEmployees finds the employees responsible for a boundary, department and designation
+func (h *EmployeeHandler) ResolveEmployees(c *gin.Context) {
+	tenantID, exists := c.Get("tenantID")
+	if !exists {
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
+		return
+	}
+
+	tID, ok := tenantID.(string)
+	if !ok {
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
+		return
+	}
+
+	criteria := &models.ResolveCriteria{}
+	if err := c.ShouldBindQuery(criteria); err != nil {
+		h.handleError(c, errors.New("INVALID_REQUEST", err.Error()))
+		return
+	}
+	criteria.TenantID = tID
+
+	// Resolution lists every matching employee of the tenant, so it never names employees
+	if err := h.requireOpenSearch(c, "ResolveEmployees"); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	resolved, err := h.service.ResolveEmployees(c.Request.Context(), criteria)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Selected points at one of the candidates, so masking the candidates covers it
+	for _, candidate := range resolved.Candidates {
+		h.maskPII(c, candidate.Employee)
+	}
+	c.JSON(http.StatusOK, resolved)
+}
+
+func (h *EmployeeHandler) CountEmployees(c *gin.Context) {
+	tenantID, exists := c.Get("tenantID")
+	if !exists {
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
+		return
+	}
+
+	tID, ok := tenantID.(string)
+	if !ok {
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
+		return
+	}
+
+	criteria := &models.EmployeeSearchCriteria{}
+	if err := c.ShouldBindQuery(criteria); err != nil {
+		h.handleError(c, errors.New("INVALID_REQUEST", err.Error()))
+		return
+	}
+	criteria.TenantID = tID
+
+	if err := h.checkOpenSearch(c, criteria, "CountEmployees"); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	counts, err := h.service.CountEmployees(c.Request.Context(), criteria)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, counts)
+}
+
 func (h *EmployeeHandler) GetEmployeeByUUID(c *gin.Context) {
 	tenantID, exists := c.Get("tenantID")
 	if !exists {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
 		return
 	}
 
 	id := c.Param("id")
 	if _, err := uuid.Parse(id); err != nil {
-		h.handleError(c, http.StatusBadRequest, errors.New("INVALID_UUID", "Invalid employee UUID"))
+		h.handleError(c, errors.New("INVALID_UUID", "Invalid employee UUID"))
 		return
 	}
 
 	tID, ok := tenantID.(string)
 	if !ok {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
 		return
 	}
 
-	employee, err := h.service.GetEmployeeByUUID(c.Request.Context(), id, tID)
+	asOf, err := parseTimeParam(c, "asOf")
 	if err != nil {
-		h.handleError(c, http.StatusInternalServerError, err)
+		h.handleError(c, err)
 		return
 	}
 
+	var employee *models.EmployeeResponse
+	if asOf != nil {
+		employee, err = h.service.GetEmployeeAsOf(c.Request.Context(), id, tID, *asOf)
+	} else {
+		employee, err = h.service.GetEmployeeByUUID(c.Request.Context(), id, tID)
+	}
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.maskPII(c, employee)
 	c.JSON(http.StatusOK, employee)
 }
 
 func (h *EmployeeHandler) UpdateEmployee(c *gin.Context) {
 	tenantID, exists := c.Get("tenantID")
 	if !exists {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
 		return
 	}
 
 	id := c.Param("id")
 	if _, err := uuid.Parse(id); err != nil {
-		h.handleError(c, http.StatusBadRequest, errors.New("INVALID_UUID", "Invalid employee UUID"))
+		h.handleError(c, errors.New("INVALID_UUID", "Invalid employee UUID"))
 		return
 	}
 
 	var req models.CreateEmployeeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.handleError(c, http.StatusBadRequest, errors.New("INVALID_REQUEST", err.Error()))
+		h.handleError(c, errors.New("INVALID_REQUEST", err.Error()))
 		return
 	}
 
 	tID, ok := tenantID.(string)
 	if !ok {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
 		return
 	}
 
 	employee, err := h.service.UpdateEmployee(c.Request.Context(), id, &req, tID)
 	if err != nil {
-		h.handleError(c, http.StatusInternalServerError, err)
+		h.handleError(c, err)
 		return
 	}
 
+	h.maskPII(c, employee)
 	c.JSON(http.StatusOK, employee)
 }
 
 func (h *EmployeeHandler) HardDeleteEmployee(c *gin.Context) {
 	tenantID, exists := c.Get("tenantID")
 	if !exists {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
 		return
 	}
 
 	id := c.Param("id")
 	if _, err := uuid.Parse(id); err != nil {
-		h.handleError(c, http.StatusBadRequest, errors.New("INVALID_UUID", "Invalid employee UUID"))
+		h.handleError(c, errors.New("INVALID_UUID", "Invalid employee UUID"))
 		return
 	}
 
 	tID, ok := tenantID.(string)
 	if !ok {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
 		return
 	}
 
 	if err := h.service.HardDeleteEmployee(c.Request.Context(), id, tID); err != nil {
-		h.handleError(c, http.StatusInternalServerError, err)
+		h.handleError(c, err)
 		return
 	}
 
@@ -176,100 +358,230 @@ func (h *EmployeeHandler) HardDeleteEmployee(c *gin.Context) {
 func (h *EmployeeHandler) PatchEmployee(c *gin.Context) {
 	tenantID, exists := c.Get("tenantID")
 	if !exists {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
 		return
 	}
 
 	id := c.Param("id")
 	if _, err := uuid.Parse(id); err != nil {
-		h.handleError(c, http.StatusBadRequest, errors.New("INVALID_UUID", "Invalid employee UUID"))
+		h.handleError(c, errors.New("INVALID_UUID", "Invalid employee UUID"))
 		return
 	}
 
 	var req models.UpdateEmployeeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.handleError(c, http.StatusBadRequest, errors.New("INVALID_REQUEST", err.Error()))
+		h.handleError(c, errors.New("INVALID_REQUEST", err.Error()))
 		return
 	}
 
 	tID, ok := tenantID.(string)
 	if !ok {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
 		return
 	}
 	employee, err := h.service.PatchEmployee(c.Request.Context(), id, &req, tID)
 	if err != nil {
-		h.handleError(c, http.StatusInternalServerError, err)
+		h.handleError(c, err)
 		return
 	}
 
+	h.maskPII(c, employee)
 	c.JSON(http.StatusOK, employee)
 }
 
 func (h *EmployeeHandler) DeactivateEmployee(c *gin.Context) {
 	tenantID, exists := c.Get("tenantID")
 	if !exists {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
 		return
 	}
 
 	id := c.Param("id")
 	if _, err := uuid.Parse(id); err != nil {
-		h.handleError(c, http.StatusBadRequest, errors.New("INVALID_UUID", "Invalid employee UUID"))
+		h.handleError(c, errors.New("INVALID_UUID", "Invalid employee UUID"))
 		return
 	}
 
 	var req models.DeactivationDetails
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.handleError(c, http.StatusBadRequest, errors.New("INVALID_REQUEST", err.Error()))
+		h.handleError(c, errors.New("INVALID_REQUEST", err.Error()))
 		return
 	}
 
 	tID, ok := tenantID.(string)
 	if !ok {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
 		return
 	}
 
 	employee, err := h.service.DeactivateEmployee(c.Request.Context(), id, &req, tID)
 	if err != nil {
-		h.handleError(c, http.StatusInternalServerError, err)
+		h.handleError(c, err)
 		return
 	}
 
+	h.maskPII(c, employee)
 	c.JSON(http.StatusOK, employee)
 }
 
 func (h *EmployeeHandler) ReactivateEmployee(c *gin.Context) {
 	tenantID, exists := c.Get("tenantID")
 	if !exists {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
 		return
 	}
 
 	id := c.Param("id")
 	if _, err := uuid.Parse(id); err != nil {
-		h.handleError(c, http.StatusBadRequest, errors.New("INVALID_UUID", "Invalid employee UUID"))
+		h.handleError(c, errors.New("INVALID_UUID", "Invalid employee UUID"))
 		return
 	}
 
 	var req models.ReactivationDetails
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.handleError(c, http.StatusBadRequest, errors.New("INVALID_REQUEST", err.Error()))
+		h.handleError(c, errors.New("INVALID_REQUEST", err.Error()))
 		return
 	}
 
 	tID, ok := tenantID.(string)
 	if !ok {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
 		return
 	}
 
 	employee, err := h.service.ReactivateEmployee(c.Request.Context(), id, &req, tID)
 	if err != nil {
-		h.handleError(c, http.StatusInternalServerError, err)
+		h.handleError(c, err)
 		return
 	}
 
+	h.maskPII(c, employee)
 	c.JSON(http.StatusOK, employee)
 }
+
+func (h *EmployeeHandler) GetStatusHistory(c *gin.Context) {
+	tenantID, exists := c.Get("tenantID")
+	if !exists {
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
+		return
+	}
+
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		h.handleError(c, errors.New("INVALID_UUID", "Invalid employee UUID"))
+		return
+	}
+
+	tID, ok := tenantID.(string)
+	if !ok {
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
+		return
+	}
+
+	history, err := h.service.GetStatusHistory(c.Request.Context(), id, tID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// GetAuditLog returns a page of the changes made to an employee, optionally filtered by
+// changed field and actor
+func (h *EmployeeHandler) GetAuditLog(c *gin.Context) {
+	tenantID, exists := c.Get("tenantID")
+	if !exists {
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
+		return
+	}
+
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		h.handleError(c, errors.New("INVALID_UUID", "Invalid employee UUID"))
+		return
+	}
+
+	tID, ok := tenantID.(string)
+	if !ok {
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
+		return
+	}
+
+	criteria := &models.AuditSearchCriteria{}
+	if err := c.ShouldBindQuery(criteria); err != nil {
+		h.handleError(c, errors.New("INVALID_REQUEST", err.Error()))
+		return
+	}
+	criteria.TenantID = tID
+
+	auditLog, err := h.service.GetAuditLog(c.Request.Context(), id, criteria)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, auditLog)
+}
+
+func (h *EmployeeHandler) ListPendingActions(c *gin.Context) {
+	tenantID, exists := c.Get("tenantID")
+	if !exists {
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
+		return
+	}
+
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		h.handleError(c, errors.New("INVALID_UUID", "Invalid employee UUID"))
+		return
+	}
+
+	tID, ok := tenantID.(string)
+	if !ok {
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
+		return
+	}
+
+	actions, err := h.service.ListPendingActions(c.Request.Context(), id, c.Query("status"), tID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, actions)
+}
+
+func (h *EmployeeHandler) CancelPendingAction(c *gin.Context) {
+	tenantID, exists := c.Get("tenantID")
+	if !exists {
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
+		return
+	}
+
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		h.handleError(c, errors.New("INVALID_UUID", "Invalid employee UUID"))
+		return
+	}
+
+	actionID := c.Param("actionId")
+	if _, err := uuid.Parse(actionID); err != nil {
+		h.handleError(c, errors.New("INVALID_UUID", "Invalid pending action UUID"))
+		return
+	}
+
+	tID, ok := tenantID.(string)
+	if !ok {
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
+		return
+	}
+
+	action, err := h.service.CancelPendingAction(c.Request.Context(), id, actionID, tID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, action)
+}
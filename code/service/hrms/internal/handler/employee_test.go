@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"hrms/internal/middleware"
+	"hrms/internal/models"
+	"hrms/internal/service"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// listingService answers the listing endpoints and records which were reached
+type listingService struct {
+	service.EmployeeService
+	called []string
+}
+
+func (s *listingService) SearchEmployees(ctx context.Context, criteria *models.EmployeeSearchCriteria) (*models.EmployeeSearchResponse, error) {
+	s.called = append(s.called, "search")
+	return &models.EmployeeSearchResponse{}, nil
+}
+
+func (s *listingService) CountEmployees(ctx context.Context, criteria *models.EmployeeSearchCriteria) (*models.EmployeeCountResponse, error) {
+	s.called = append(s.called, "count")
+	return &models.EmployeeCountResponse{}, nil
+}
+
+func (s *listingService) ResolveEmployees(ctx context.Context, criteria *models.ResolveCriteria) (*models.ResolveResponse, error) {
+	s.called = append(s.called, "resolve")
+	return &models.ResolveResponse{}, nil
+}
+
+func newListingEngine(h *EmployeeHandler, roles []string) *gin.Engine {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+
+	r := gin.New()
+	r.Use(middleware.ErrorHandler(logger), func(c *gin.Context) {
+		c.Set("tenantID", "pg.citya")
+		c.Set("roles", roles)
+		c.Next()
+	})
+	r.GET("/employees", h.SearchEmployees)
+	r.GET("/employees/_count", h.CountEmployees)
+	r.GET("/employees/_resolve", h.ResolveEmployees)
+	return r
+}
+
+func TestOpenSearchRestriction(t *testing.T) {
+	openSearchRoles := []string{"SUPERUSER", "ADMIN"}
+
+	tests := []struct {
+		name            string
+		openSearchRoles []string
+		roles           []string
+		url             string
+		wantStatus      int
+	}{
+		{name: "search by uuids", openSearchRoles: openSearchRoles, url: "/employees?uuids=6f1c3a52-0c6c-4bb4-8a8e-3b1d8b0c2a11", wantStatus: http.StatusOK},
+		{name: "search by codes", openSearchRoles: openSearchRoles, url: "/employees?codes=EMP-1", wantStatus: http.StatusOK},
+		{name: "search by phone", openSearchRoles: openSearchRoles, url: "/employees?phone=9876543210", wantStatus: http.StatusOK},
+		{name: "open search without role", openSearchRoles: openSearchRoles, roles: []string{"EMPLOYEE"}, url: "/employees?departments=DEPT_1", wantStatus: http.StatusForbidden},
+		{name: "open search with role", openSearchRoles: openSearchRoles, roles: []string{"EMPLOYEE", "ADMIN"}, url: "/employees?departments=DEPT_1", wantStatus: http.StatusOK},
+		{name: "open search unrestricted without auth", openSearchRoles: nil, url: "/employees", wantStatus: http.StatusOK},
+
+		{name: "count by codes", openSearchRoles: openSearchRoles, url: "/employees/_count?codes=EMP-1", wantStatus: http.StatusOK},
+		{name: "open count without role", openSearchRoles: openSearchRoles, roles: []string{"EMPLOYEE"}, url: "/employees/_count?isActive=true", wantStatus: http.StatusForbidden},
+		{name: "open count with role", openSearchRoles: openSearchRoles, roles: []string{"SUPERUSER"}, url: "/employees/_count", wantStatus: http.StatusOK},
+
+		{name: "resolve without role", openSearchRoles: openSearchRoles, roles: []string{"EMPLOYEE"}, url: "/employees/_resolve?boundary=WARD_1", wantStatus: http.StatusForbidden},
+		{name: "resolve with role", openSearchRoles: openSearchRoles, roles: []string{"SUPERUSER"}, url: "/employees/_resolve?boundary=WARD_1", wantStatus: http.StatusOK},
+		{name: "resolve unrestricted without auth", openSearchRoles: nil, url: "/employees/_resolve?boundary=WARD_1", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &listingService{}
+			h := NewEmployeeHandler(svc, logrus.New(), nil, tt.openSearchRoles)
+
+			w := httptest.NewRecorder()
+			newListingEngine(h, tt.roles).ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body: %s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if reached := len(svc.called) > 0; reached != (tt.wantStatus == http.StatusOK) {
+				t.Fatalf("service reached = %v for status %d", reached, w.Code)
+			}
+		})
+	}
+}
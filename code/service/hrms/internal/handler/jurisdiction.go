@@ -3,6 +3,7 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -36,13 +37,13 @@ type createJurisdictionRequest struct {
 func (h *JurisdictionHandler) SearchJurisdictions(c *gin.Context) {
 	tenantID, exists := c.Get("tenantID")
 	if !exists {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
 		return
 	}
 
 	tID, ok := tenantID.(string)
 	if !ok {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
 		return
 	}
 
@@ -60,6 +61,13 @@ func (h *JurisdictionHandler) SearchJurisdictions(c *gin.Context) {
 		criteria.IsActive = &active
 	}
 
+	asOf, err := parseTimeParam(c, "asOf")
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	criteria.AsOf = asOf
+
 	// Set pagination
 	if limit, err := parseIntParam(c, "limit", 10); err == nil {
 		criteria.Limit = limit
@@ -71,7 +79,7 @@ func (h *JurisdictionHandler) SearchJurisdictions(c *gin.Context) {
 	// Search jurisdictions
 	jurisdictions, err := h.service.SearchJurisdictions(c.Request.Context(), criteria)
 	if err != nil {
-		h.handleError(c, http.StatusInternalServerError, err)
+		h.handleError(c, err)
 		return
 	}
 
@@ -81,29 +89,25 @@ func (h *JurisdictionHandler) SearchJurisdictions(c *gin.Context) {
 func (h *JurisdictionHandler) GetJurisdictionByUUID(c *gin.Context) {
 	tenantID, exists := c.Get("tenantID")
 	if !exists {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
 		return
 	}
 
 	uuidStr := c.Param("uuid")
 	if _, err := uuid.Parse(uuidStr); err != nil {
-		h.handleError(c, http.StatusBadRequest, errors.New("INVALID_UUID", "Invalid jurisdiction UUID"))
+		h.handleError(c, errors.New("INVALID_UUID", "Invalid jurisdiction UUID"))
 		return
 	}
 
 	tID, ok := tenantID.(string)
 	if !ok {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
 		return
 	}
 
 	jurisdiction, err := h.service.GetJurisdictionByUUID(c.Request.Context(), uuidStr, tID)
 	if err != nil {
-		if errors.Is(err, errors.ErrNotFound) {
-			h.handleError(c, http.StatusNotFound, errors.New("NOT_FOUND", "Jurisdiction not found"))
-			return
-		}
-		h.handleError(c, http.StatusInternalServerError, err)
+		h.handleError(c, err)
 		return
 	}
 
@@ -113,25 +117,25 @@ func (h *JurisdictionHandler) GetJurisdictionByUUID(c *gin.Context) {
 func (h *JurisdictionHandler) CreateJurisdiction(c *gin.Context) {
 	tenantID, exists := c.Get("tenantID")
 	if !exists {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
 		return
 	}
 
 	tID, ok := tenantID.(string)
 	if !ok {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
 		return
 	}
 
 	var req models.CreateJurisdictionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.handleError(c, http.StatusBadRequest, errors.New("INVALID_REQUEST", err.Error()))
+		h.handleError(c, errors.New("INVALID_REQUEST", err.Error()))
 		return
 	}
 
 	jurisdiction, err := h.service.CreateJurisdiction(c.Request.Context(), &req, tID)
 	if err != nil {
-		h.handleError(c, http.StatusInternalServerError, err)
+		h.handleError(c, err)
 		return
 	}
 
@@ -141,58 +145,177 @@ func (h *JurisdictionHandler) CreateJurisdiction(c *gin.Context) {
 func (h *JurisdictionHandler) ReplaceJurisdiction(c *gin.Context) {
 	tenantID, exists := c.Get("tenantID")
 	if !exists {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
 		return
 	}
 
 	uuidStr := c.Param("uuid")
 	if _, err := uuid.Parse(uuidStr); err != nil {
-		h.handleError(c, http.StatusBadRequest, errors.New("INVALID_UUID", "Invalid jurisdiction UUID"))
+		h.handleError(c, errors.New("INVALID_UUID", "Invalid jurisdiction UUID"))
 		return
 	}
 
 	var req models.UpdateJurisdictionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.handleError(c, http.StatusBadRequest, errors.New("INVALID_REQUEST", err.Error()))
+		h.handleError(c, errors.New("INVALID_REQUEST", err.Error()))
 		return
 	}
 
 	tID, ok := tenantID.(string)
 	if !ok {
-		h.handleError(c, http.StatusInternalServerError, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Invalid tenant ID format"))
 		return
 	}
 
 	jurisdiction, err := h.service.ReplaceJurisdiction(c.Request.Context(), uuidStr, &req, tID)
 	if err != nil {
-		h.handleError(c, http.StatusInternalServerError, err)
+		h.handleError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"jurisdiction": jurisdiction})
 }
 
-func (h *JurisdictionHandler) handleError(c *gin.Context, statusCode int, err error) {
-	c.JSON(statusCode, gin.H{
-		"error": gin.H{
-			"code":    getErrorCode(err),
-			"message": getErrorMessage(err),
-		},
-	})
+// UpdateJurisdiction partially updates a jurisdiction; omitted fields keep their values
+func (h *JurisdictionHandler) UpdateJurisdiction(c *gin.Context) {
+	tID, uuidStr, ok := h.jurisdictionParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateJurisdictionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleError(c, errors.New("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	jurisdiction, err := h.service.UpdateJurisdiction(c.Request.Context(), uuidStr, &req, tID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"jurisdiction": jurisdiction})
+}
+
+// DeleteJurisdiction deletes a jurisdiction
+func (h *JurisdictionHandler) DeleteJurisdiction(c *gin.Context) {
+	tID, uuidStr, ok := h.jurisdictionParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteJurisdiction(c.Request.Context(), uuidStr, tID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetEmployeeJurisdictions lists the jurisdictions of an employee, only those valid at the
+// asOf query parameter when given
+func (h *JurisdictionHandler) GetEmployeeJurisdictions(c *gin.Context) {
+	tID, employeeID, ok := h.employeeParams(c)
+	if !ok {
+		return
+	}
+
+	asOf, err := parseTimeParam(c, "asOf")
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	jurisdictions, err := h.service.GetJurisdictionsByEmployeeID(c.Request.Context(), employeeID, tID, asOf)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"jurisdictions": jurisdictions})
+}
+
+// CreateEmployeeJurisdiction adds a jurisdiction to an employee. The employee is taken
+// from the path; employeeId in the body is ignored.
+func (h *JurisdictionHandler) CreateEmployeeJurisdiction(c *gin.Context) {
+	tID, employeeID, ok := h.employeeParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateJurisdictionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleError(c, errors.New("INVALID_REQUEST", err.Error()))
+		return
+	}
+	req.EmployeeID = employeeID
+
+	jurisdiction, err := h.service.CreateJurisdiction(c.Request.Context(), &req, tID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"jurisdiction": jurisdiction})
 }
 
-func getErrorCode(err error) string {
-	if e, ok := err.(interface{ Code() string }); ok {
-		return e.Code()
+type replaceJurisdictionsRequest struct {
+	Jurisdictions []*models.CreateJurisdictionRequest `json:"jurisdictions" binding:"required"`
+}
+
+// ReplaceEmployeeJurisdictions replaces all jurisdictions of an employee with the given list
+func (h *JurisdictionHandler) ReplaceEmployeeJurisdictions(c *gin.Context) {
+	tID, employeeID, ok := h.employeeParams(c)
+	if !ok {
+		return
+	}
+
+	var req replaceJurisdictionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleError(c, errors.New("INVALID_REQUEST", err.Error()))
+		return
 	}
-	return "INTERNAL_ERROR"
+
+	jurisdictions, err := h.service.ReplaceEmployeeJurisdictions(c.Request.Context(), employeeID, req.Jurisdictions, tID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"jurisdictions": jurisdictions})
 }
 
-func getErrorMessage(err error) string {
-	if e, ok := err.(interface{ Error() string }); ok {
-		return e.Error()
+// jurisdictionParams reads the tenant and the jurisdiction UUID of the request
+func (h *JurisdictionHandler) jurisdictionParams(c *gin.Context) (string, string, bool) {
+	return h.requestParams(c, "uuid", "Invalid jurisdiction UUID")
+}
+
+// employeeParams reads the tenant and the employee UUID of an employee-scoped request
+func (h *JurisdictionHandler) employeeParams(c *gin.Context) (string, string, bool) {
+	return h.requestParams(c, "id", "Invalid employee UUID")
+}
+
+func (h *JurisdictionHandler) requestParams(c *gin.Context, param, invalidMessage string) (string, string, bool) {
+	tID := c.GetString("tenantID")
+	if tID == "" {
+		h.handleError(c, errors.New("INTERNAL_ERROR", "Tenant ID not found in context"))
+		return "", "", false
+	}
+
+	id := c.Param(param)
+	if _, err := uuid.Parse(id); err != nil {
+		h.handleError(c, errors.New("INVALID_UUID", invalidMessage))
+		return "", "", false
 	}
-	return "An unexpected error occurred"
+
+	return tID, id, true
+}
+
+// handleError attaches the error to the request; middleware.ErrorHandler renders it
+func (h *JurisdictionHandler) handleError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
 }
 
 // parseIntParam is a helper function to parse integer query parameters
@@ -203,3 +326,19 @@ func parseIntParam(c *gin.Context, param string, defaultValue int) (int, error)
 	}
 	return strconv.Atoi(value)
 }
+
+// parseTimeParam parses an optional time query parameter given as an RFC 3339 timestamp or
+// a date. A date stands for the start of that day in UTC.
+func parseTimeParam(c *gin.Context, param string) (*time.Time, error) {
+	value := c.Query(param)
+	if value == "" {
+		return nil, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, errors.NewFieldError(param, param+" must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+}
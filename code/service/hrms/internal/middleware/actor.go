@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"hrms/internal/requestctx"
+)
+
+// Actor stores the caller of the request in the request context, so that writes record
+// who made them. It must run after Headers and Auth.
+func Actor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := requestctx.Actor{
+			UserID:   c.GetString("userID"),
+			ClientID: c.GetString("clientID"),
+		}
+		c.Request = c.Request.WithContext(requestctx.WithActor(c.Request.Context(), actor))
+
+		c.Next()
+	}
+}
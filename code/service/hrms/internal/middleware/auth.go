@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"hrms/internal/auth"
+	"hrms/pkg/errors"
+)
+
+const authorizationHeader = "Authorization"
+
+// Auth is a middleware that requires a valid bearer token for the tenant of the request.
+// It must run after Headers. The caller's user ID, tenants and roles are stored in the
+// context as "userID", "tenants" and "roles".
+func Auth(verifier *auth.Verifier, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Skip for health check endpoint
+		if c.Request.URL.Path == "/health" {
+			c.Next()
+			return
+		}
+
+		scheme, token, _ := strings.Cut(c.GetHeader(authorizationHeader), " ")
+		if !strings.EqualFold(scheme, "Bearer") || token == "" {
+			_ = c.Error(errors.New("UNAUTHORIZED", "bearer token is required"))
+			c.Abort()
+			return
+		}
+
+		principal, err := verifier.Verify(strings.TrimSpace(token))
+		if err != nil {
+			logger.WithError(err).Debug("Rejected bearer token")
+			_ = c.Error(errors.New("UNAUTHORIZED", "invalid bearer token"))
+			c.Abort()
+			return
+		}
+
+		tenantID := c.GetString("tenantID")
+		if !principal.HasTenant(tenantID) {
+			_ = c.Error(errors.New("FORBIDDEN", "access to tenant denied").
+				WithDescription("the token does not grant access to tenant " + tenantID))
+			c.Abort()
+			return
+		}
+
+		c.Set("userID", principal.UserID)
+		c.Set("tenants", principal.Tenants)
+		c.Set("roles", principal.Roles)
+
+		c.Next()
+	}
+}
+
+// RequireRoles is a middleware that lets a request through only if the caller holds
+// at least one of the roles set by Auth
+func RequireRoles(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		held := &auth.Principal{Roles: c.GetStringSlice("roles")}
+		if !held.HasAnyRole(roles...) {
+			_ = c.Error(errors.New("FORBIDDEN", "insufficient role").
+				WithDescription("this operation requires one of the roles " + strings.Join(roles, ", ")))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"hrms/internal/auth"
+)
+
+const testSharedKey = "0123456789abcdef0123456789abcdef"
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// testToken builds an HS256 token for the given tenants and roles
+func testToken(t *testing.T, tenants, roles []string) string {
+	t.Helper()
+	enc := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := enc(map[string]string{"alg": "HS256", "typ": "JWT"}) + "." + enc(map[string]interface{}{
+		"sub":     "user-1",
+		"exp":     time.Now().Add(time.Hour).Unix(),
+		"tenants": tenants,
+		"roles":   roles,
+	})
+	mac := hmac.New(sha256.New, []byte(testSharedKey))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// newTestEngine serves GET /read for any authenticated caller and POST /write for
+// callers holding HRMS_ADMIN or SUPERUSER
+func newTestEngine(t *testing.T) *gin.Engine {
+	t.Helper()
+	verifier, err := auth.NewVerifier(auth.Config{SharedKey: testSharedKey})
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+
+	r := gin.New()
+	r.Use(ErrorHandler(logger), Headers(logger), Auth(verifier, logger))
+	ok := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"userID": c.GetString("userID")}) }
+	r.GET("/health", ok)
+	r.GET("/read", ok)
+	r.POST("/write", RequireRoles("HRMS_ADMIN", "SUPERUSER"), ok)
+	return r
+}
+
+func TestAuthAndRoles(t *testing.T) {
+	tests := []struct {
+		name          string
+		method, path  string
+		tenantID      string
+		authorization string
+		wantStatus    int
+	}{
+		{name: "health needs no token", method: http.MethodGet, path: "/health", wantStatus: http.StatusOK},
+		{name: "missing token", method: http.MethodGet, path: "/read", tenantID: "pg.citya", wantStatus: http.StatusUnauthorized},
+		{name: "not a bearer token", method: http.MethodGet, path: "/read", tenantID: "pg.citya",
+			authorization: "Basic dXNlcjpwYXNz", wantStatus: http.StatusUnauthorized},
+		{name: "invalid token", method: http.MethodGet, path: "/read", tenantID: "pg.citya",
+			authorization: "Bearer not.a.token", wantStatus: http.StatusUnauthorized},
+		{name: "read in own tenant", method: http.MethodGet, path: "/read", tenantID: "pg.citya",
+			authorization: "Bearer " + testToken(t, []string{"pg.citya"}, nil), wantStatus: http.StatusOK},
+		{name: "read in sub-tenant", method: http.MethodGet, path: "/read", tenantID: "pg.citya",
+			authorization: "bearer " + testToken(t, []string{"pg"}, nil), wantStatus: http.StatusOK},
+		{name: "read in another tenant", method: http.MethodGet, path: "/read", tenantID: "pg.cityb",
+			authorization: "Bearer " + testToken(t, []string{"pg.citya"}, nil), wantStatus: http.StatusForbidden},
+		{name: "write with admin role", method: http.MethodPost, path: "/write", tenantID: "pg.citya",
+			authorization: "Bearer " + testToken(t, []string{"pg.citya"}, []string{"HRMS_ADMIN"}), wantStatus: http.StatusOK},
+		{name: "write with superuser role", method: http.MethodPost, path: "/write", tenantID: "pg.citya",
+			authorization: "Bearer " + testToken(t, []string{"pg.citya"}, []string{"EMPLOYEE", "SUPERUSER"}), wantStatus: http.StatusOK},
+		{name: "write without a write role", method: http.MethodPost, path: "/write", tenantID: "pg.citya",
+			authorization: "Bearer " + testToken(t, []string{"pg.citya"}, []string{"EMPLOYEE"}), wantStatus: http.StatusForbidden},
+		{name: "write with admin role in another tenant", method: http.MethodPost, path: "/write", tenantID: "pg.cityb",
+			authorization: "Bearer " + testToken(t, []string{"pg.citya"}, []string{"HRMS_ADMIN"}), wantStatus: http.StatusForbidden},
+	}
+
+	r := newTestEngine(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.tenantID != "" {
+				req.Header.Set("X-Tenant-ID", tt.tenantID)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body: %s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"hrms/internal/models"
+	"hrms/pkg/errors"
+)
+
+// errorStatus maps pkg/errors codes to HTTP statuses. Codes that are not listed
+// are treated as internal errors.
+var errorStatus = map[string]int{
+	// Malformed or invalid requests
+	"INVALID_INPUT":               http.StatusBadRequest,
+	"INVALID_REQUEST":             http.StatusBadRequest,
+	"INVALID_UUID":                http.StatusBadRequest,
+	"MISSING_HEADER":              http.StatusBadRequest,
+	"VALIDATION_ERROR":            http.StatusBadRequest,
+	"ASSIGNMENT_OVERLAP":          http.StatusBadRequest,
+	"MULTIPLE_ACTIVE_ASSIGNMENTS": http.StatusBadRequest,
+	"INVALID_BOUNDARY":            http.StatusBadRequest,
+
+	// Authentication and authorisation
+	"UNAUTHORIZED": http.StatusUnauthorized,
+	"FORBIDDEN":    http.StatusForbidden,
+
+	// Missing resources
+	"NOT_FOUND":              http.StatusNotFound,
+	"EMPLOYEE_NOT_FOUND":     http.StatusNotFound,
+	"JURISDICTION_NOT_FOUND": http.StatusNotFound,
+
+	// Requests that conflict with the current state of a resource
+	"EMPLOYEE_EXISTS":                http.StatusConflict,
+	"JURISDICTION_EXISTS":            http.StatusConflict,
+	"EMPLOYEE_HAS_ASSIGNMENTS":       http.StatusConflict,
+	"EMPLOYEE_HAS_STATUS_HISTORY":    http.StatusConflict,
+	"EMPLOYEE_ACTIVE":                http.StatusConflict,
+	"EMPLOYEE_DEACTIVATED":           http.StatusConflict,
+	"PENDING_ACTION_EXISTS":          http.StatusConflict,
+	"PENDING_ACTION_NOT_CANCELLABLE": http.StatusConflict,
+
+	// Failures of the service or its dependencies
+	"ID_GENERATION_ERROR":      http.StatusBadGateway,
+	"INDIVIDUAL_SERVICE_ERROR": http.StatusBadGateway,
+	"USER_SERVICE_ERROR":       http.StatusBadGateway,
+	"BOUNDARY_SERVICE_ERROR":   http.StatusBadGateway,
+	"DATABASE_ERROR":           http.StatusInternalServerError,
+	"INTERNAL_ERROR":           http.StatusInternalServerError,
+}
+
+// ErrorHandler renders the last error attached to the context with c.Error as an
+// ErrorResponse, choosing the HTTP status from the error code
+func ErrorHandler(logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		status, resp := toErrorResponse(err)
+
+		entry := logger.WithError(err).WithField("status", status)
+		var e *errors.Error
+		if stderrors.As(err, &e) && e.Op != "" {
+			entry = entry.WithField("op", e.Op)
+		}
+		if status >= http.StatusInternalServerError {
+			entry.Error("Request failed")
+		} else {
+			entry.Warn("Request rejected")
+		}
+
+		c.AbortWithStatusJSON(status, resp)
+	}
+}
+
+// Recovery converts a panic into an internal error so that it is rendered by ErrorHandler
+func Recovery(logger *logrus.Logger) gin.HandlerFunc {
+	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
+		logger.WithField("panic", recovered).Error("Recovered from panic")
+		_ = c.Error(errors.ErrInternalServer)
+		c.Abort()
+	})
+}
+
+// toErrorResponse builds the HTTP status and response body for an error. A MultiError
+// is rendered as one entry per error and takes the status of its first error. Each error
+// is rendered as the innermost error of its chain with a known code, so a not found,
+// conflict or validation error keeps its status when a caller wraps it.
+func toErrorResponse(err error) (int, models.ErrorResponse) {
+	var errs []*errors.Error
+	var multi *errors.MultiError
+	if stderrors.As(err, &multi) {
+		for _, e := range multi.Errors {
+			errs = append(errs, innermostKnown(e))
+		}
+	} else if e := innermostKnown(err); e != nil {
+		errs = []*errors.Error{e}
+	}
+	if len(errs) == 0 {
+		errs = []*errors.Error{errors.ErrInternalServer}
+	}
+
+	status, known := errorStatus[errs[0].Code]
+	if !known {
+		status = http.StatusInternalServerError
+	}
+
+	resp := models.ErrorResponse{Errors: make([]models.Error, 0, len(errs))}
+	for _, e := range errs {
+		resp.Errors = append(resp.Errors, models.Error{
+			Code:        e.Code,
+			Message:     e.Message,
+			Description: e.Description,
+			Params:      e.Params,
+		})
+	}
+
+	return status, resp
+}
+
+// innermostKnown walks the chain of wrapped errors and returns the innermost *errors.Error
+// whose code has a status, or the outermost *errors.Error when none has. Params of the
+// errors wrapping the returned one, such as the item index of a bulk request, are added
+// to it where it does not set them itself. It returns nil when the chain holds no
+// *errors.Error.
+func innermostKnown(err error) *errors.Error {
+	var chain []*errors.Error
+	known := 0
+	var e *errors.Error
+	for stderrors.As(err, &e) {
+		if _, ok := errorStatus[e.Code]; ok {
+			known = len(chain)
+		}
+		chain = append(chain, e)
+		err = e.Err
+	}
+	if len(chain) == 0 {
+		return nil
+	}
+
+	rendered := chain[known]
+	for i := known - 1; i >= 0; i-- {
+		own := rendered.ParamMap()
+		for key, value := range chain[i].ParamMap() {
+			if _, ok := own[key]; !ok {
+				rendered = rendered.WithParam(key, value)
+			}
+		}
+	}
+	return rendered
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"hrms/pkg/errors"
+)
+
+func TestToErrorResponse(t *testing.T) {
+	multi := &errors.MultiError{}
+	multi.Add(errors.Wrap(errors.NewFieldError("code", "code is required"), "INTERNAL_ERROR", "create failed"))
+	multi.Add(errors.ErrNotFound.WithDescription("employee not found"))
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCodes  []string
+		// wantParams lists the params of each rendered error, when checked
+		wantParams []string
+	}{
+		{name: "not found", err: errors.ErrNotFound, wantStatus: http.StatusNotFound, wantCodes: []string{"NOT_FOUND"}},
+		{name: "validation", err: errors.NewFieldError("code", "code is required"), wantStatus: http.StatusBadRequest, wantCodes: []string{"VALIDATION_ERROR"}},
+		{
+			name:       "not found wrapped as a database error",
+			err:        errors.Wrap(errors.ErrNotFound.WithDescription("employee not found"), "DATABASE_ERROR", "lookup failed"),
+			wantStatus: http.StatusNotFound,
+			wantCodes:  []string{"NOT_FOUND"},
+		},
+		{
+			name:       "conflict wrapped with fmt.Errorf",
+			err:        fmt.Errorf("create jurisdiction: %w", errors.ErrJurisdictionExists),
+			wantStatus: http.StatusConflict,
+			wantCodes:  []string{"JURISDICTION_EXISTS"},
+		},
+		{
+			name:       "validation wrapped twice",
+			err:        errors.Wrap(fmt.Errorf("validate: %w", errors.NewFieldError("code", "code is required")), "INTERNAL_ERROR", "failed"),
+			wantStatus: http.StatusBadRequest,
+			wantCodes:  []string{"VALIDATION_ERROR"},
+		},
+		{
+			name:       "database failure keeps its own code",
+			err:        errors.Wrap(stderrors.New("connection reset"), "DATABASE_ERROR", "lookup failed"),
+			wantStatus: http.StatusInternalServerError,
+			wantCodes:  []string{"DATABASE_ERROR"},
+		},
+		{
+			name:       "unknown inner code falls back to the known outer one",
+			err:        errors.Wrap(errors.New("SOMETHING_ELSE", "odd"), "USER_SERVICE_ERROR", "user service failed"),
+			wantStatus: http.StatusBadGateway,
+			wantCodes:  []string{"USER_SERVICE_ERROR"},
+		},
+		{
+			name:       "bulk item index kept on a wrapped not found",
+			err:        errors.Wrap(errors.ErrNotFound, "DATABASE_ERROR", "lookup failed").WithParam("index", 2),
+			wantStatus: http.StatusNotFound,
+			wantCodes:  []string{"NOT_FOUND"},
+			wantParams: []string{"map[index:2]"},
+		},
+		{
+			name:       "bulk item index added to the field of a wrapped validation error",
+			err:        errors.Wrap(errors.NewFieldError("code", "code is required"), "INTERNAL_ERROR", "create failed").WithParam("index", 1),
+			wantStatus: http.StatusBadRequest,
+			wantCodes:  []string{"VALIDATION_ERROR"},
+			wantParams: []string{"map[field:code index:1]"},
+		},
+		{
+			name: "params of the rendered error win over outer ones",
+			err: errors.Wrap(errors.NewFieldError("code", "code is required"), "INTERNAL_ERROR", "create failed").
+				WithParams(map[string]string{"field": "employees", "index": "3"}),
+			wantStatus: http.StatusBadRequest,
+			wantCodes:  []string{"VALIDATION_ERROR"},
+			wantParams: []string{"map[field:code index:3]"},
+		},
+		{
+			name:       "outer params that are not a map are kept",
+			err:        errors.Wrap(errors.ErrNotFound, "DATABASE_ERROR", "lookup failed").WithParams([]string{"WARD-1"}),
+			wantStatus: http.StatusNotFound,
+			wantCodes:  []string{"NOT_FOUND"},
+			wantParams: []string{"map[params:[WARD-1]]"},
+		},
+		{
+			name:       "params of an outermost known error are unchanged",
+			err:        errors.NewFieldError("code", "code is required"),
+			wantStatus: http.StatusBadRequest,
+			wantCodes:  []string{"VALIDATION_ERROR"},
+			wantParams: []string{"map[field:code]"},
+		},
+		{
+			name:       "unknown code is internal",
+			err:        errors.New("SOMETHING_ELSE", "odd"),
+			wantStatus: http.StatusInternalServerError,
+			wantCodes:  []string{"SOMETHING_ELSE"},
+		},
+		{
+			name:       "plain error is internal",
+			err:        stderrors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantCodes:  []string{"INTERNAL_ERROR"},
+		},
+		{
+			name:       "multi error entries are unwrapped, status of the first",
+			err:        multi,
+			wantStatus: http.StatusBadRequest,
+			wantCodes:  []string{"VALIDATION_ERROR", "NOT_FOUND"},
+		},
+		{
+			name:       "wrapped multi error",
+			err:        fmt.Errorf("bulk create: %w", multi),
+			wantStatus: http.StatusBadRequest,
+			wantCodes:  []string{"VALIDATION_ERROR", "NOT_FOUND"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := toErrorResponse(tt.err)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if len(resp.Errors) != len(tt.wantCodes) {
+				t.Fatalf("got %d errors, want %d: %+v", len(resp.Errors), len(tt.wantCodes), resp.Errors)
+			}
+			for i, code := range tt.wantCodes {
+				if resp.Errors[i].Code != code {
+					t.Fatalf("error %d code = %s, want %s", i, resp.Errors[i].Code, code)
+				}
+			}
+			for i, params := range tt.wantParams {
+				if got := fmt.Sprint(resp.Errors[i].Params); got != params {
+					t.Fatalf("error %d params = %s, want %s", i, got, params)
+				}
+			}
+		})
+	}
+}
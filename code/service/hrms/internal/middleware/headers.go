@@ -1,8 +1,6 @@
 package middleware
 
 import (
-	"net/http"
-
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 
@@ -27,10 +25,8 @@ func Headers(logger *logrus.Logger) gin.HandlerFunc {
 		tentantID := c.GetHeader(tentantIDHeader)
 		if tentantID == "" {
 			err := errors.New("MISSING_HEADER", "X-Tenant-ID header is required")
-			logger.WithError(err).Error("Missing required header")
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"error": err.Error(),
-			})
+			_ = c.Error(err)
+			c.Abort()
 			return
 		}
 
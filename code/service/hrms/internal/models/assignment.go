@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Assignment represents a time-bound designation held by an employee within a department
+type Assignment struct {
+	ID                  string     `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	EmployeeID          string     `json:"employeeId" gorm:"not null;index"`
+	Department          string     `json:"department" gorm:"not null"`
+	Designation         string     `json:"designation" gorm:"not null"`
+	ReportingTo         string     `json:"reportingTo,omitempty"`
+	IsHOD               bool       `json:"isHOD" gorm:"column:is_hod;default:false"`
+	IsCurrentAssignment bool       `json:"isCurrentAssignment" gorm:"default:false"`
+	FromDate            *time.Time `json:"fromDate" gorm:"not null"`
+	ToDate              *time.Time `json:"toDate,omitempty"`
+	TenantID            string     `json:"tenantId" gorm:"not null;index"`
+	CreatedBy           string     `json:"-" gorm:"not null"`
+	LastModifiedBy      *string    `json:"-"`
+	CreatedTime         int64      `json:"createdAt" gorm:"column:created_time;not null"`
+	LastModifiedTime    *int64     `json:"updatedAt,omitempty" gorm:"column:last_modified_time"`
+}
+
+// TableName specifies the table name for the Assignment model
+func (Assignment) TableName() string {
+	return "eg_hrms_assignment_v3"
+}
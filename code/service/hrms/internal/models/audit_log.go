@@ -0,0 +1,61 @@
+package models
+
+// Audited entity types
+const (
+	AuditEntityEmployee     = "EMPLOYEE"
+	AuditEntityJurisdiction = "JURISDICTION"
+)
+
+// Audited operations
+const (
+	AuditOperationCreate = "CREATE"
+	AuditOperationUpdate = "UPDATE"
+	AuditOperationDelete = "DELETE"
+)
+
+// AuditLog is one change to an employee or jurisdiction. Entries are only ever appended.
+type AuditLog struct {
+	ID         int64  `json:"id" gorm:"primaryKey;autoIncrement"`
+	EntityType string `json:"entityType" gorm:"not null"`
+	EntityID   string `json:"entityId" gorm:"not null"`
+	// EmployeeID is the employee the entity is, or belongs to
+	EmployeeID string `json:"employeeId" gorm:"not null"`
+	Operation  string `json:"operation" gorm:"not null"`
+	// Changes maps the API name of each changed field to its old and new value
+	Changes     map[string]FieldChange `json:"changes" gorm:"type:jsonb;serializer:json"`
+	Actor       string                 `json:"actor" gorm:"not null"`
+	TenantID    string                 `json:"tenantId" gorm:"not null"`
+	CreatedTime int64                  `json:"createdAt" gorm:"column:created_time;not null"`
+}
+
+// TableName specifies the table name for the AuditLog model
+func (AuditLog) TableName() string {
+	return "eg_hrms_audit_log"
+}
+
+// FieldChange is the old and new value of a field. Old is null on create and New is null
+// on delete. Values of encrypted personal fields are not recorded; Redacted marks them.
+type FieldChange struct {
+	Old      interface{} `json:"old"`
+	New      interface{} `json:"new"`
+	Redacted bool        `json:"redacted,omitempty"`
+}
+
+// AuditSearchCriteria filters the audit log of an employee
+type AuditSearchCriteria struct {
+	// Field keeps entries that changed the field, named as in the API
+	Field      string `form:"field"`
+	Actor      string `form:"actor"`
+	Limit      int    `form:"limit,default=10"`
+	Offset     int    `form:"offset,default=0"`
+	EmployeeID string `form:"-"`
+	TenantID   string `form:"-"`
+}
+
+// AuditLogResponse represents a page of audit log entries, newest first
+type AuditLogResponse struct {
+	Entries    []*AuditLog `json:"entries"`
+	TotalCount int64       `json:"totalCount"`
+	Limit      int         `json:"limit"`
+	Offset     int         `json:"offset"`
+}
@@ -2,12 +2,12 @@ package models
 
 import "fmt"
 
-// AuditDetails holds audit information for database records
+// AuditDetails records who created and last modified a record and when, as Unix seconds
 type AuditDetails struct {
-	CreatedBy    string `json:"createdBy,omitempty" gorm:"column:createdby;type:varchar(250)"`
-	LastModified string `json:"lastModified,omitempty" gorm:"column:lastmodified;type:varchar(64)"`
-	CreatedTime  int64  `json:"createdTime,omitempty" gorm:"column:createdtime;type:bigint"`
-	UpdatedTime  int64  `json:"updatedTime,omitempty" gorm:"column:lastmodifiedtime;type:bigint"`
+	CreatedBy        string  `json:"createdBy,omitempty"`
+	LastModifiedBy   *string `json:"lastModifiedBy,omitempty"`
+	CreatedTime      int64   `json:"createdTime,omitempty"`
+	LastModifiedTime *int64  `json:"lastModifiedTime,omitempty"`
 }
 
 // ErrorResponse represents an error response in the API
@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"hrms/internal/pii"
 )
 
 // Employee represents an employee in the system
@@ -16,6 +18,14 @@ type Employee struct {
 	Department        string        `json:"department,omitempty" gorm:"not null"`
 	Designation       string        `json:"designation,omitempty" gorm:"not null"`
 	IsActive          bool          `json:"isActive,omitempty" gorm:"default:true"`
+	MobileNumber      string        `json:"mobileNumber,omitempty" gorm:"serializer:encrypted"`
+	MobileNumberHash  string        `json:"-"`
+	Email             string        `json:"email,omitempty" gorm:"serializer:encrypted"`
+	DateOfBirth       *time.Time    `json:"dateOfBirth,omitempty"`
+	Gender            string        `json:"gender,omitempty"`
+	PanNumber         string        `json:"panNumber,omitempty" gorm:"serializer:encrypted"`
+	AadhaarNumber     string        `json:"aadhaarNumber,omitempty" gorm:"serializer:encrypted"`
+	DateOfRetirement  *time.Time    `json:"dateOfRetirement,omitempty"`
 	Jurisdictions     []*Jurisdiction `json:"jurisdictions,omitempty" gorm:"foreignKey:EmployeeID"`
 	TenantID          string        `json:"-"`
 	CreatedBy         string        `json:"-"`
@@ -35,16 +45,48 @@ type CreateEmployeeRequest struct {
 	Department        string        `json:"department,omitempty"`
 	Designation       string        `json:"designation,omitempty"`
 	IsActive          *bool         `json:"isActive,omitempty"`
+	Name              *PersonName   `json:"name,omitempty"`
+	MobileNumber      string        `json:"mobileNumber,omitempty"`
+	Email             string        `json:"email,omitempty"`
+	DateOfBirth       *time.Time    `json:"dateOfBirth,omitempty"`
+	Gender            string        `json:"gender,omitempty"`
+	PanNumber         string        `json:"panNumber,omitempty"`
+	AadhaarNumber     string        `json:"aadhaarNumber,omitempty"`
+	DateOfRetirement  *time.Time    `json:"dateOfRetirement,omitempty"`
 	Jurisdictions     []*Jurisdiction `json:"jurisdictions,omitempty"`
+	Assignments       []*Assignment   `json:"assignments,omitempty"`
+	ServiceHistory    []*ServiceHistory `json:"serviceHistory,omitempty"`
+	Education         []*EducationalDetail `json:"education,omitempty"`
+	Tests             []*DepartmentalTest  `json:"tests,omitempty"`
 }
 
 // UpdateEmployeeRequest represents the request payload for updating an employee
 type UpdateEmployeeRequest struct {
-	EmployeeStatus *string `json:"employeeStatus,omitempty"`
-	EmployeeType   *string `json:"employeeType,omitempty"`
-	Phone          *string `json:"phone,omitempty"`
-	EmailId        *string `json:"emailId,omitempty"`
-	IsActive       *bool   `json:"isActive,omitempty"`
+	EmployeeStatus   *string    `json:"employeeStatus,omitempty"`
+	EmployeeType     *string    `json:"employeeType,omitempty"`
+	Phone            *string    `json:"phone,omitempty"`
+	EmailId          *string    `json:"emailId,omitempty"`
+	DateOfBirth      *time.Time `json:"dateOfBirth,omitempty"`
+	Gender           *string    `json:"gender,omitempty"`
+	PanNumber        *string    `json:"panNumber,omitempty"`
+	AadhaarNumber    *string    `json:"aadhaarNumber,omitempty"`
+	DateOfRetirement *time.Time `json:"dateOfRetirement,omitempty"`
+	IsActive         *bool      `json:"isActive,omitempty"`
+}
+
+// PersonName is the name of the person behind an employee record
+type PersonName struct {
+	GivenName  string `json:"givenName"`
+	FamilyName string `json:"familyName,omitempty"`
+	OtherNames string `json:"otherNames,omitempty"`
+}
+
+// IndividualDetails is the name and contact information of the linked Individual record
+type IndividualDetails struct {
+	ID           string     `json:"id"`
+	Name         PersonName `json:"name"`
+	MobileNumber string     `json:"mobileNumber,omitempty"`
+	Email        string     `json:"email,omitempty"`
 }
 
 // EmployeeResponse represents the response payload for employee operations
@@ -59,22 +101,117 @@ type EmployeeResponse struct {
 	Department        string                `json:"department,omitempty"`
 	Designation       string                `json:"designation,omitempty"`
 	IsActive          bool                  `json:"isActive"`
+	MobileNumber      string                `json:"mobileNumber,omitempty"`
+	Email             string                `json:"email,omitempty"`
+	DateOfBirth       *time.Time            `json:"dateOfBirth,omitempty"`
+	Gender            string                `json:"gender,omitempty"`
+	PanNumber         string                `json:"panNumber,omitempty"`
+	AadhaarNumber     string                `json:"aadhaarNumber,omitempty"`
+	DateOfRetirement  *time.Time            `json:"dateOfRetirement,omitempty"`
+	Individual        *IndividualDetails    `json:"individual,omitempty"`
 	Jurisdictions     []*JurisdictionResponse `json:"jurisdictions,omitempty"`
+	Assignments       []*Assignment           `json:"assignments,omitempty"`
+	ServiceHistory    []*ServiceHistory       `json:"serviceHistory,omitempty"`
+	Education         []*EducationalDetail    `json:"education,omitempty"`
+	Tests             []*DepartmentalTest     `json:"tests,omitempty"`
+	StatusHistory     []*StatusHistory        `json:"statusHistory,omitempty"`
+	PendingActions    []*PendingStatusAction  `json:"pendingActions,omitempty"`
+	AuditDetails      *AuditDetails           `json:"auditDetails,omitempty"`
+	// InitialPassword is the generated password of a login account created with the
+	// employee. It is only returned by create, once, and is never stored or logged.
+	InitialPassword string `json:"initialPassword,omitempty"`
+}
+
+// MaskPII masks the personal identifiers of the employee in place
+func (r *EmployeeResponse) MaskPII() {
+	if r == nil {
+		return
+	}
+	r.MobileNumber = pii.Mask(r.MobileNumber)
+	r.Email = pii.Mask(r.Email)
+	r.PanNumber = pii.Mask(r.PanNumber)
+	r.AadhaarNumber = pii.Mask(r.AadhaarNumber)
+	if r.Individual != nil {
+		r.Individual.MobileNumber = pii.Mask(r.Individual.MobileNumber)
+		r.Individual.Email = pii.Mask(r.Individual.Email)
+	}
+}
+
+// EmployeeSearchResponse represents a page of employee search results
+type EmployeeSearchResponse struct {
+	Employees  []*EmployeeResponse `json:"employees"`
+	TotalCount int64               `json:"totalCount"`
+	Limit      int                 `json:"limit"`
+	Offset     int                 `json:"offset"`
+}
+
+// EmployeeBulkResult reports the outcome of one item of a partial-mode bulk create
+type EmployeeBulkResult struct {
+	Index    int               `json:"index"`
+	Success  bool              `json:"success"`
+	Employee *EmployeeResponse `json:"employee,omitempty"`
+	Errors   []*Error          `json:"errors,omitempty"`
+}
+
+// EmployeeCountResponse represents employee totals split by active state and grouped by attribute
+type EmployeeCountResponse struct {
+	Total          int64            `json:"total"`
+	Active         int64            `json:"active"`
+	Inactive       int64            `json:"inactive"`
+	ByStatus       map[string]int64 `json:"byStatus"`
+	ByEmployeeType map[string]int64 `json:"byEmployeeType"`
+	ByDepartment   map[string]int64 `json:"byDepartment"`
 }
 
 // EmployeeSearchCriteria represents the search criteria for employees
 type EmployeeSearchCriteria struct {
-	UUIDs        []string `form:"uuids"`
-	Codes        []string `form:"codes"`
-	Departments  []string `form:"departments"`
-	Designations []string `form:"designations"`
-	Phone        string   `form:"phone"`
-	IsActive     *bool    `form:"isActive"`
-	Limit        int      `form:"limit,default=10"`
-	Offset       int      `form:"offset,default=0"`
-	SortBy       string   `form:"sortBy,default=createdAt"`
-	SortOrder    string   `form:"sortOrder,default=desc"`
-	TenantID     string
+	UUIDs              []string `form:"uuids"`
+	Codes              []string `form:"codes"`
+	Departments        []string `form:"departments"`
+	Designations       []string `form:"designations"`
+	Phone              string   `form:"phone"`
+	BoundaryCodes      []string `form:"boundaryCodes"`
+	IncludeDescendants bool     `form:"includeDescendants"`
+	IsActive           *bool    `form:"isActive"`
+	Limit              int      `form:"limit,default=10"`
+	Offset             int      `form:"offset,default=0"`
+	SortBy             string   `form:"sortBy,default=createdAt"`
+	SortOrder          string   `form:"sortOrder,default=desc"`
+	// AsOf searches the employees as they were at that time, rebuilt from their history
+	AsOf     *time.Time `form:"-"`
+	TenantID string     `form:"-"`
+}
+
+// Selection strategies for resolving the employee responsible for a boundary
+const (
+	ResolveStrategyRoundRobin  = "round_robin"
+	ResolveStrategyLeastLoaded = "least_loaded"
+)
+
+// ResolveCriteria identifies the location and role to find responsible employees for
+type ResolveCriteria struct {
+	Boundary    string `form:"boundary"`
+	Department  string `form:"department"`
+	Designation string `form:"designation"`
+	Strategy    string `form:"strategy"`
+	TenantID    string `form:"-"`
+}
+
+// ResolvedEmployee is an employee with an active jurisdiction over the requested boundary
+// or one of its ancestors
+type ResolvedEmployee struct {
+	Employee *EmployeeResponse `json:"employee"`
+	// Boundary is the boundary of the matching jurisdiction
+	Boundary string `json:"boundary"`
+	// Distance is the number of levels between the requested boundary and Boundary
+	Distance int `json:"distance"`
+}
+
+// ResolveResponse lists the employees responsible for a boundary, most specific first
+type ResolveResponse struct {
+	Boundary   string              `json:"boundary"`
+	Candidates []*ResolvedEmployee `json:"candidates"`
+	Selected   *ResolvedEmployee   `json:"selected,omitempty"`
 }
 
 // TableName specifies the table name for the Employee model
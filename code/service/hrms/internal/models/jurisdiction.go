@@ -1,16 +1,26 @@
 package models
 
-// Jurisdiction represents a jurisdiction in the system
+import "time"
+
+// Jurisdiction represents a jurisdiction in the system. A jurisdiction is a boundary of a
+// given boundary type within a hierarchy; BoundaryRelation holds the boundary codes from
+// the root of the hierarchy down to and including Boundary. A jurisdiction holds from
+// ValidFrom until ValidTo; an open-ended one has no ValidTo.
 type Jurisdiction struct {
-	ID               string   `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
-	EmployeeID       string   `json:"employeeId" gorm:"not null;index"`
-	BoundaryRelation []string `json:"boundaryRelation" gorm:"type:jsonb;serializer:json"`
-	IsActive         bool     `json:"isActive" gorm:"default:true"`
-	TenantID         string   `json:"tenantId" gorm:"not null;index"`
-	CreatedBy        string   `json:"-" gorm:"not null"`
-	LastModifiedBy   *string  `json:"-"`
-	CreatedTime      int64    `json:"createdAt" gorm:"column:created_time;not null"`
-	LastModifiedTime *int64   `json:"updatedAt,omitempty" gorm:"column:last_modified_time"`
+	ID               string     `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	EmployeeID       string     `json:"employeeId" gorm:"not null;index"`
+	Hierarchy        string     `json:"hierarchy"`
+	BoundaryType     string     `json:"boundaryType"`
+	Boundary         string     `json:"boundary"`
+	BoundaryRelation []string   `json:"boundaryRelation" gorm:"type:jsonb;serializer:json"`
+	ValidFrom        *time.Time `json:"validFrom" gorm:"not null"`
+	ValidTo          *time.Time `json:"validTo,omitempty"`
+	IsActive         bool       `json:"isActive" gorm:"default:true"`
+	TenantID         string     `json:"tenantId" gorm:"not null;index"`
+	CreatedBy        string     `json:"-" gorm:"not null"`
+	LastModifiedBy   *string    `json:"-"`
+	CreatedTime      int64      `json:"createdAt" gorm:"column:created_time;not null"`
+	LastModifiedTime *int64     `json:"updatedAt,omitempty" gorm:"column:last_modified_time"`
 }
 
 // TableName specifies the table name for the Jurisdiction model
@@ -18,40 +28,62 @@ func (Jurisdiction) TableName() string {
 	return "eg_hrms_jurisdiction_v3"
 }
 
-// CreateJurisdictionRequest represents the request payload for creating a jurisdiction
+// CreateJurisdictionRequest represents the request payload for creating a jurisdiction.
+// Either Boundary or BoundaryRelation must be given; without Boundary the last code of
+// BoundaryRelation is used. ValidFrom defaults to the time of creation.
 type CreateJurisdictionRequest struct {
-	EmployeeID       string   `json:"employeeId" binding:"required"`
-	BoundaryRelation []string `json:"boundaryRelation" validate:"required,min=1"`
-	IsActive         *bool    `json:"isActive"`
+	EmployeeID       string     `json:"employeeId"`
+	Hierarchy        string     `json:"hierarchy"`
+	BoundaryType     string     `json:"boundaryType"`
+	Boundary         string     `json:"boundary"`
+	BoundaryRelation []string   `json:"boundaryRelation"`
+	ValidFrom        *time.Time `json:"validFrom"`
+	ValidTo          *time.Time `json:"validTo"`
+	IsActive         *bool      `json:"isActive"`
 }
 
 // UpdateJurisdictionRequest represents the request payload for updating a jurisdiction
 type UpdateJurisdictionRequest struct {
-	EmployeeID       string    `json:"employeeId,omitempty"`
-	BoundaryRelation *[]string `json:"boundaryRelation,omitempty" validate:"omitempty,min=1"`
-	IsActive         *bool     `json:"isActive,omitempty"`
+	EmployeeID       string     `json:"employeeId,omitempty"`
+	Hierarchy        string     `json:"hierarchy,omitempty"`
+	BoundaryType     string     `json:"boundaryType,omitempty"`
+	Boundary         string     `json:"boundary,omitempty"`
+	BoundaryRelation *[]string  `json:"boundaryRelation,omitempty" validate:"omitempty,min=1"`
+	ValidFrom        *time.Time `json:"validFrom,omitempty"`
+	ValidTo          *time.Time `json:"validTo,omitempty"`
+	IsActive         *bool      `json:"isActive,omitempty"`
 }
 
 // JurisdictionResponse represents the response payload for jurisdiction operations
 type JurisdictionResponse struct {
-	ID               string   `json:"id"`
-	EmployeeID       string   `json:"employeeId"`
-	BoundaryRelation []string `json:"boundaryRelation"`
-	IsActive         bool     `json:"isActive"`
-	TenantID         string   `json:"tenantId"`
-	CreatedTime      int64    `json:"createdAt"`
-	LastModifiedTime *int64   `json:"updatedAt"`
+	ID               string        `json:"id"`
+	EmployeeID       string        `json:"employeeId"`
+	Hierarchy        string        `json:"hierarchy"`
+	BoundaryType     string        `json:"boundaryType"`
+	Boundary         string        `json:"boundary"`
+	BoundaryRelation []string      `json:"boundaryRelation"`
+	ValidFrom        *time.Time    `json:"validFrom"`
+	ValidTo          *time.Time    `json:"validTo,omitempty"`
+	IsActive         bool          `json:"isActive"`
+	TenantID         string        `json:"tenantId"`
+	CreatedTime      int64         `json:"createdAt"`
+	LastModifiedTime *int64        `json:"updatedAt"`
+	AuditDetails     *AuditDetails `json:"auditDetails,omitempty"`
 }
 
-// JurisdictionSearchCriteria represents the search criteria for jurisdictions
+// JurisdictionSearchCriteria represents the search criteria for jurisdictions. AsOf limits
+// the result to jurisdictions valid at that time. RecordedAt searches the jurisdictions as
+// they were stored at that time instead of as they are now.
 type JurisdictionSearchCriteria struct {
-	IDs         []string `form:"ids"`
-	EmployeeID  string   `form:"employeeId"`
-	EmployeeIDs []string `form:"employeeIds"`
-	IsActive    *bool    `form:"isActive"`
-	Limit       int      `form:"limit,default=10"`
-	Offset      int      `form:"offset,default=0"`
-	SortBy      string   `form:"sortBy,default=createdAt"`
-	SortOrder   string   `form:"sortOrder,default=desc"`
+	IDs         []string   `form:"ids"`
+	EmployeeID  string     `form:"employeeId"`
+	EmployeeIDs []string   `form:"employeeIds"`
+	IsActive    *bool      `form:"isActive"`
+	AsOf        *time.Time `form:"asOf"`
+	RecordedAt  *time.Time `form:"-"`
+	Limit       int        `form:"limit,default=10"`
+	Offset      int        `form:"offset,default=0"`
+	SortBy      string     `form:"sortBy,default=createdAt"`
+	SortOrder   string     `form:"sortOrder,default=desc"`
 	TenantID    string
 }
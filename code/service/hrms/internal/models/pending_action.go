@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Pending action lifecycle states
+const (
+	PendingActionStatusPending   = "PENDING"
+	PendingActionStatusApplied   = "APPLIED"
+	PendingActionStatusCancelled = "CANCELLED"
+	PendingActionStatusFailed    = "FAILED"
+)
+
+// PendingStatusAction is a deactivation or reactivation scheduled to take effect at EffectiveFrom
+type PendingStatusAction struct {
+	ID               string     `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	EmployeeID       string     `json:"employeeId" gorm:"not null;index"`
+	Action           string     `json:"action" gorm:"not null"`
+	Reason           string     `json:"reason" gorm:"not null"`
+	EffectiveFrom    *time.Time `json:"effectiveFrom" gorm:"not null"`
+	Remarks          string     `json:"remarks,omitempty"`
+	Status           string     `json:"status" gorm:"not null"`
+	FailureReason    string     `json:"failureReason,omitempty"`
+	TenantID         string     `json:"tenantId" gorm:"not null;index"`
+	CreatedBy        string     `json:"createdBy" gorm:"not null"`
+	CreatedTime      int64      `json:"createdAt" gorm:"column:created_time;not null"`
+	LastModifiedTime *int64     `json:"updatedAt,omitempty" gorm:"column:last_modified_time"`
+}
+
+// TableName specifies the table name for the PendingStatusAction model
+func (PendingStatusAction) TableName() string {
+	return "eg_hrms_pending_status_action"
+}
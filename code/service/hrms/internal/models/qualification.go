@@ -0,0 +1,41 @@
+package models
+
+// EducationalDetail represents an educational qualification of an employee
+type EducationalDetail struct {
+	ID               string  `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	EmployeeID       string  `json:"employeeId" gorm:"not null;index"`
+	Degree           string  `json:"degree" gorm:"not null"`
+	Specialization   string  `json:"specialization,omitempty"`
+	University       string  `json:"university,omitempty"`
+	YearOfPassing    int     `json:"yearOfPassing" gorm:"not null"`
+	Remarks          string  `json:"remarks,omitempty"`
+	TenantID         string  `json:"tenantId" gorm:"not null;index"`
+	CreatedBy        string  `json:"-" gorm:"not null"`
+	LastModifiedBy   *string `json:"-"`
+	CreatedTime      int64   `json:"createdAt" gorm:"column:created_time;not null"`
+	LastModifiedTime *int64  `json:"updatedAt,omitempty" gorm:"column:last_modified_time"`
+}
+
+// TableName specifies the table name for the EducationalDetail model
+func (EducationalDetail) TableName() string {
+	return "eg_hrms_educational_details_v3"
+}
+
+// DepartmentalTest represents a departmental test passed by an employee
+type DepartmentalTest struct {
+	ID               string  `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	EmployeeID       string  `json:"employeeId" gorm:"not null;index"`
+	Test             string  `json:"test" gorm:"not null"`
+	YearOfPassing    int     `json:"yearOfPassing" gorm:"not null"`
+	Remarks          string  `json:"remarks,omitempty"`
+	TenantID         string  `json:"tenantId" gorm:"not null;index"`
+	CreatedBy        string  `json:"-" gorm:"not null"`
+	LastModifiedBy   *string `json:"-"`
+	CreatedTime      int64   `json:"createdAt" gorm:"column:created_time;not null"`
+	LastModifiedTime *int64  `json:"updatedAt,omitempty" gorm:"column:last_modified_time"`
+}
+
+// TableName specifies the table name for the DepartmentalTest model
+func (DepartmentalTest) TableName() string {
+	return "eg_hrms_departmental_tests_v3"
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// ServiceHistory represents a record of an employee's past or current service.
+// Periods of different records may overlap, as they are captured as legacy data.
+type ServiceHistory struct {
+	ID                string     `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	EmployeeID        string     `json:"employeeId" gorm:"not null;index"`
+	ServiceStatus     string     `json:"serviceStatus,omitempty"`
+	ServiceFrom       *time.Time `json:"serviceFrom,omitempty"`
+	ServiceTo         *time.Time `json:"serviceTo,omitempty"`
+	OrderNo           string     `json:"orderNo,omitempty"`
+	Location          string     `json:"location,omitempty"`
+	IsCurrentPosition bool       `json:"isCurrentPosition" gorm:"default:false"`
+	TenantID          string     `json:"tenantId" gorm:"not null;index"`
+	CreatedBy         string     `json:"-" gorm:"not null"`
+	LastModifiedBy    *string    `json:"-"`
+	CreatedTime       int64      `json:"createdAt" gorm:"column:created_time;not null"`
+	LastModifiedTime  *int64     `json:"updatedAt,omitempty" gorm:"column:last_modified_time"`
+}
+
+// TableName specifies the table name for the ServiceHistory model
+func (ServiceHistory) TableName() string {
+	return "eg_hrms_service_history_v3"
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Status change actions recorded in the status history
+const (
+	StatusActionDeactivation = "DEACTIVATION"
+	StatusActionReactivation = "REACTIVATION"
+)
+
+// StatusHistory records a single deactivation or reactivation of an employee
+type StatusHistory struct {
+	ID            string     `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	EmployeeID    string     `json:"employeeId" gorm:"not null;index"`
+	Action        string     `json:"action" gorm:"not null"`
+	Reason        string     `json:"reason" gorm:"not null"`
+	EffectiveFrom *time.Time `json:"effectiveFrom"`
+	Remarks       string     `json:"remarks,omitempty"`
+	TenantID      string     `json:"tenantId" gorm:"not null;index"`
+	CreatedBy     string     `json:"createdBy" gorm:"not null"`
+	CreatedTime   int64      `json:"createdAt" gorm:"column:created_time;not null"`
+}
+
+// TableName specifies the table name for the StatusHistory model
+func (StatusHistory) TableName() string {
+	return "eg_hrms_deactivation_details"
+}
@@ -0,0 +1,146 @@
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ciphertextPrefix marks values written by the key ring
+const ciphertextPrefix = "enc:"
+
+// IsEncrypted reports whether a stored value was written by a key ring
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, ciphertextPrefix)
+}
+
+// KeyRing encrypts values with AES-GCM under the active key and decrypts values
+// written under any key it holds, so keys can be rotated without re-encrypting
+// existing rows. It also produces deterministic hashes for equality lookups.
+type KeyRing struct {
+	activeKeyID string
+	keys        map[string]cipher.AEAD
+	hashKey     []byte
+}
+
+// Config holds key ring configuration
+type Config struct {
+	// Keys maps key IDs to base64 encoded 16, 24 or 32 byte AES keys
+	Keys map[string]string
+	// ActiveKeyID selects the key used for new values
+	ActiveKeyID string
+	// HashKey is the secret used for deterministic lookup hashes
+	HashKey string
+}
+
+// NewKeyRing creates a key ring from configuration
+func NewKeyRing(cfg Config) (*KeyRing, error) {
+	if len(cfg.Keys) == 0 {
+		return nil, fmt.Errorf("at least one encryption key is required")
+	}
+	if _, ok := cfg.Keys[cfg.ActiveKeyID]; !ok {
+		return nil, fmt.Errorf("active encryption key %q is not configured", cfg.ActiveKeyID)
+	}
+	if cfg.HashKey == "" {
+		return nil, fmt.Errorf("hash key is required")
+	}
+
+	keys := make(map[string]cipher.AEAD, len(cfg.Keys))
+	for id, encoded := range cfg.Keys {
+		if strings.Contains(id, ":") {
+			return nil, fmt.Errorf("encryption key ID %q must not contain ':'", id)
+		}
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q is not valid base64: %w", id, err)
+		}
+		block, err := aes.NewCipher(raw)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q: %w", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q: %w", id, err)
+		}
+		keys[id] = aead
+	}
+
+	return &KeyRing{
+		activeKeyID: cfg.ActiveKeyID,
+		keys:        keys,
+		hashKey:     []byte(cfg.HashKey),
+	}, nil
+}
+
+// Encrypt encrypts a value under the active key as enc:<keyID>:<base64(nonce|ciphertext)>.
+// Empty values are returned unchanged.
+func (k *KeyRing) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	aead := k.keys[k.activeKeyID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(k.activeKeyID))
+	return ciphertextPrefix + k.activeKeyID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt reverses Encrypt. Empty values are returned unchanged; any other value that was
+// not written by Encrypt is rejected, as plaintext left over from before encryption must
+// be encrypted by the startup backfill rather than read as it is.
+func (k *KeyRing) Decrypt(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	if !IsEncrypted(value) {
+		return "", fmt.Errorf("value is not encrypted")
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(value, ciphertextPrefix), ":", 2)
+	if len(parts) != 2 {
+		return "", fmt.Errorf("malformed encrypted value")
+	}
+	keyID, encoded := parts[0], parts[1]
+
+	aead, ok := k.keys[keyID]
+	if !ok {
+		return "", fmt.Errorf("encryption key %q is not configured", keyID)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted value: %w", err)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted value")
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(keyID))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// Hash returns a deterministic HMAC-SHA256 of a value for equality lookups.
+// Empty values hash to the empty string.
+func (k *KeyRing) Hash(value string) string {
+	if value == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, k.hashKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,215 @@
+package pii
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32)))
+}
+
+func newTestKeyRing(t *testing.T, active string, keys map[string]string, hashKey string) *KeyRing {
+	t.Helper()
+	k, err := NewKeyRing(Config{Keys: keys, ActiveKeyID: active, HashKey: hashKey})
+	if err != nil {
+		t.Fatalf("NewKeyRing: %v", err)
+	}
+	return k
+}
+
+func TestNewKeyRing(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{name: "valid", cfg: Config{Keys: map[string]string{"k1": testKey('a')}, ActiveKeyID: "k1", HashKey: "h"}},
+		{name: "no keys", cfg: Config{ActiveKeyID: "k1", HashKey: "h"}, wantErr: "at least one encryption key"},
+		{name: "unknown active key", cfg: Config{Keys: map[string]string{"k1": testKey('a')}, ActiveKeyID: "k2", HashKey: "h"}, wantErr: "is not configured"},
+		{name: "no hash key", cfg: Config{Keys: map[string]string{"k1": testKey('a')}, ActiveKeyID: "k1"}, wantErr: "hash key is required"},
+		{name: "key ID with colon", cfg: Config{Keys: map[string]string{"k:1": testKey('a')}, ActiveKeyID: "k:1", HashKey: "h"}, wantErr: "must not contain ':'"},
+		{name: "key not base64", cfg: Config{Keys: map[string]string{"k1": "not base64!"}, ActiveKeyID: "k1", HashKey: "h"}, wantErr: "not valid base64"},
+		{name: "key of wrong length", cfg: Config{Keys: map[string]string{"k1": base64.StdEncoding.EncodeToString([]byte("short"))}, ActiveKeyID: "k1", HashKey: "h"}, wantErr: "invalid key size"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewKeyRing(tt.cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	k := newTestKeyRing(t, "k1", map[string]string{"k1": testKey('a')}, "hash-secret")
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "mobile number", value: "9876543210"},
+		{name: "email", value: "asha.rao@example.org"},
+		{name: "aadhaar with spaces", value: "1234 5678 9012"},
+		{name: "non-ascii", value: "आशा राव"},
+		{name: "value with separator", value: "enc:k1:looks-encrypted"},
+		{name: "empty", value: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encrypted, err := k.Encrypt(tt.value)
+			if err != nil {
+				t.Fatalf("Encrypt: %v", err)
+			}
+			if tt.value == "" {
+				if encrypted != "" {
+					t.Fatalf("Encrypt(\"\") = %q, want empty", encrypted)
+				}
+			} else {
+				if !IsEncrypted(encrypted) || !strings.HasPrefix(encrypted, "enc:k1:") {
+					t.Fatalf("Encrypt = %q, want an enc:k1: value", encrypted)
+				}
+				if strings.Contains(encrypted, tt.value) {
+					t.Fatalf("ciphertext %q contains the plaintext", encrypted)
+				}
+			}
+
+			decrypted, err := k.Decrypt(encrypted)
+			if err != nil {
+				t.Fatalf("Decrypt: %v", err)
+			}
+			if decrypted != tt.value {
+				t.Fatalf("Decrypt = %q, want %q", decrypted, tt.value)
+			}
+		})
+	}
+}
+
+func TestEncryptUsesFreshNonce(t *testing.T) {
+	k := newTestKeyRing(t, "k1", map[string]string{"k1": testKey('a')}, "hash-secret")
+
+	first, _ := k.Encrypt("9876543210")
+	second, _ := k.Encrypt("9876543210")
+	if first == second {
+		t.Fatalf("two encryptions of the same value are equal: %q", first)
+	}
+}
+
+func TestDecryptAfterKeyRotation(t *testing.T) {
+	old := newTestKeyRing(t, "k1", map[string]string{"k1": testKey('a')}, "hash-secret")
+	encrypted, err := old.Encrypt("9876543210")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	rotated := newTestKeyRing(t, "k2", map[string]string{"k1": testKey('a'), "k2": testKey('b')}, "hash-secret")
+	decrypted, err := rotated.Decrypt(encrypted)
+	if err != nil || decrypted != "9876543210" {
+		t.Fatalf("Decrypt after rotation = %q, %v", decrypted, err)
+	}
+
+	reencrypted, _ := rotated.Encrypt("9876543210")
+	if !strings.HasPrefix(reencrypted, "enc:k2:") {
+		t.Fatalf("new value %q not written under the active key", reencrypted)
+	}
+	if old.Hash("9876543210") != rotated.Hash("9876543210") {
+		t.Fatalf("hash changed with the encryption key")
+	}
+}
+
+func TestDecryptRejects(t *testing.T) {
+	k := newTestKeyRing(t, "k1", map[string]string{"k1": testKey('a')}, "hash-secret")
+	valid, _ := k.Encrypt("9876543210")
+	other := newTestKeyRing(t, "k9", map[string]string{"k9": testKey('c')}, "hash-secret")
+	unknownKey, _ := other.Encrypt("9876543210")
+	wrongKey := newTestKeyRing(t, "k1", map[string]string{"k1": testKey('z')}, "hash-secret")
+
+	sealed, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(valid, "enc:k1:"))
+	sealed[len(sealed)-1] ^= 0xff
+	tampered := "enc:k1:" + base64.StdEncoding.EncodeToString(sealed)
+
+	tests := []struct {
+		name    string
+		keys    *KeyRing
+		value   string
+		wantErr string
+	}{
+		{name: "plaintext", keys: k, value: "9876543210", wantErr: "not encrypted"},
+		{name: "missing key ID", keys: k, value: "enc:abc", wantErr: "malformed"},
+		{name: "bad base64", keys: k, value: "enc:k1:%%%", wantErr: "malformed"},
+		{name: "too short", keys: k, value: "enc:k1:" + base64.StdEncoding.EncodeToString([]byte("x")), wantErr: "malformed"},
+		{name: "unknown key", keys: k, value: unknownKey, wantErr: "is not configured"},
+		{name: "tampered ciphertext", keys: k, value: tampered, wantErr: "failed to decrypt"},
+		{name: "same key ID, different key", keys: wrongKey, value: valid, wantErr: "failed to decrypt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.keys.Decrypt(tt.value)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Decrypt = %q, %v; want error containing %q", got, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHash(t *testing.T) {
+	k := newTestKeyRing(t, "k1", map[string]string{"k1": testKey('a')}, "hash-secret")
+	otherSecret := newTestKeyRing(t, "k1", map[string]string{"k1": testKey('a')}, "other-secret")
+
+	tests := []struct {
+		name      string
+		a, b      string
+		keys      *KeyRing
+		wantEqual bool
+	}{
+		{name: "same value hashes equal", a: "9876543210", b: "9876543210", keys: k, wantEqual: true},
+		{name: "different values differ", a: "9876543210", b: "9876543211", keys: k},
+		{name: "different secret differs", a: "9876543210", b: "9876543210", keys: otherSecret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ha, hb := k.Hash(tt.a), tt.keys.Hash(tt.b)
+			if len(ha) != 64 || strings.Contains(ha, tt.a) {
+				t.Fatalf("Hash = %q, want a 64 character hex digest", ha)
+			}
+			if (ha == hb) != tt.wantEqual {
+				t.Fatalf("Hash(%q) == Hash(%q) is %v, want %v", tt.a, tt.b, ha == hb, tt.wantEqual)
+			}
+		})
+	}
+
+	if got := k.Hash(""); got != "" {
+		t.Fatalf("Hash(\"\") = %q, want empty", got)
+	}
+}
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "", want: ""},
+		{value: "123", want: "XXX"},
+		{value: "1234", want: "XXXX"},
+		{value: "9876541234", want: "XXXXXX1234"},
+		{value: "asha@example.org", want: "XXXXXXXXXXXX.org"},
+	}
+
+	for _, tt := range tests {
+		if got := Mask(tt.value); got != tt.want {
+			t.Errorf("Mask(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
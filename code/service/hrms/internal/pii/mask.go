@@ -0,0 +1,20 @@
+package pii
+
+import "strings"
+
+// visibleChars is the number of trailing characters left readable by Mask
+const visibleChars = 4
+
+// Mask replaces all but the last four characters of a value with X,
+// e.g. 9876541234 becomes XXXXXX1234. Values of four characters or fewer are fully masked.
+func Mask(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	runes := []rune(value)
+	if len(runes) <= visibleChars {
+		return strings.Repeat("X", len(runes))
+	}
+	return strings.Repeat("X", len(runes)-visibleChars) + string(runes[len(runes)-visibleChars:])
+}
@@ -0,0 +1,53 @@
+package pii
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName is the GORM serializer name used on encrypted string fields,
+// e.g. `gorm:"serializer:encrypted"`
+const SerializerName = "encrypted"
+
+// RegisterSerializer registers the encrypted GORM serializer backed by the key ring.
+// It must be called before any model using the serializer is read or written.
+func RegisterSerializer(keys *KeyRing) {
+	schema.RegisterSerializer(SerializerName, serializer{keys: keys})
+}
+
+// serializer encrypts string fields on write and decrypts them on read
+type serializer struct {
+	keys *KeyRing
+}
+
+// Scan implements schema.SerializerInterface
+func (s serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var stored string
+	switch v := dbValue.(type) {
+	case nil:
+	case string:
+		stored = v
+	case []byte:
+		stored = string(v)
+	default:
+		return fmt.Errorf("unsupported value %T for encrypted field %s", dbValue, field.Name)
+	}
+
+	plaintext, err := s.keys.Decrypt(stored)
+	if err != nil {
+		return fmt.Errorf("field %s: %w", field.Name, err)
+	}
+	return field.Set(ctx, dst, plaintext)
+}
+
+// Value implements schema.SerializerValuerInterface
+func (s serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plaintext, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("encrypted field %s must be a string, got %T", field.Name, fieldValue)
+	}
+	return s.keys.Encrypt(plaintext)
+}
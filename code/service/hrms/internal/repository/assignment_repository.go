@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"hrms/internal/models"
+	"hrms/pkg/errors"
+)
+
+// AssignmentRepository defines the interface for assignment data access operations.
+// Assignments are part of an employee's service record and are never hard-deleted,
+// so no Delete operation is exposed.
+type AssignmentRepository interface {
+	// Create creates a new assignment
+	Create(ctx context.Context, assignment *models.Assignment) error
+
+	// FindByUUID finds an assignment by UUID
+	FindByUUID(ctx context.Context, uuid, tenantID string) (*models.Assignment, error)
+
+	// FindByEmployeeID returns all assignments of an employee ordered by from_date
+	FindByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.Assignment, error)
+
+	// Update updates an existing assignment
+	Update(ctx context.Context, assignment *models.Assignment) error
+}
+
+type assignmentRepository struct {
+	db *gorm.DB
+}
+
+// NewAssignmentRepository creates a new assignment repository
+func NewAssignmentRepository(db *gorm.DB) AssignmentRepository {
+	return &assignmentRepository{
+		db: db,
+	}
+}
+
+func (r *assignmentRepository) Create(ctx context.Context, assignment *models.Assignment) error {
+	now := time.Now().Unix()
+	assignment.CreatedTime = now
+	assignment.LastModifiedTime = &now
+
+	tx := conn(ctx, r.db).Model(&models.Assignment{}).Create(assignment)
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to create assignment")
+	}
+	return nil
+}
+
+func (r *assignmentRepository) FindByUUID(ctx context.Context, uuid, tenantID string) (*models.Assignment, error) {
+	var assignment models.Assignment
+	tx := conn(ctx, r.db).Model(&models.Assignment{}).
+		Where("id = ? AND tenant_id = ?", uuid, tenantID).
+		First(&assignment)
+
+	if tx.Error != nil {
+		if tx.Error == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotFound.WithDescription("assignment not found")
+		}
+		return nil, errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to find assignment")
+	}
+
+	return &assignment, nil
+}
+
+func (r *assignmentRepository) FindByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.Assignment, error) {
+	var assignments []*models.Assignment
+	tx := conn(ctx, r.db).Model(&models.Assignment{}).
+		Where("employee_id = ? AND tenant_id = ?", employeeID, tenantID).
+		Order("from_date ASC").
+		Find(&assignments)
+
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to find assignments by employee ID")
+	}
+
+	return assignments, nil
+}
+
+func (r *assignmentRepository) Update(ctx context.Context, assignment *models.Assignment) error {
+	now := time.Now().Unix()
+	assignment.LastModifiedTime = &now
+
+	// Select all columns so that zero values (e.g. isCurrentAssignment=false) are persisted
+	tx := conn(ctx, r.db).Model(&models.Assignment{}).
+		Where("id = ? AND tenant_id = ?", assignment.ID, assignment.TenantID).
+		Select("*").
+		Omit("id", "employee_id", "tenant_id", "created_by", "created_time").
+		Updates(assignment)
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to update assignment")
+	}
+	if tx.RowsAffected == 0 {
+		return errors.ErrNotFound.WithDescription("assignment not found")
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"hrms/internal/models"
+)
+
+// unauditedFields are identifying fields, recorded on the entry itself, and bookkeeping
+// fields that change on every write
+var unauditedFields = map[string]bool{
+	"id":        true,
+	"tenantId":  true,
+	"createdAt": true,
+	"updatedAt": true,
+}
+
+// fieldChanges compares two versions of a record field by field and returns the changed
+// ones by their JSON name. A nil old is a create and a nil new a delete. Fields hidden
+// from JSON and nested records are skipped, and values of encrypted fields are redacted.
+func fieldChanges[T any](old, new *T) map[string]models.FieldChange {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	changes := make(map[string]models.FieldChange)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" || name == "" || unauditedFields[name] || isNested(f.Type) {
+			continue
+		}
+
+		var before, after interface{}
+		if old != nil {
+			before = auditValue(reflect.ValueOf(old).Elem().Field(i))
+		}
+		if new != nil {
+			after = auditValue(reflect.ValueOf(new).Elem().Field(i))
+		}
+		if reflect.DeepEqual(before, after) {
+			continue
+		}
+
+		if strings.Contains(f.Tag.Get("gorm"), "serializer:encrypted") {
+			changes[name] = models.FieldChange{Redacted: true}
+			continue
+		}
+		changes[name] = models.FieldChange{Old: before, New: after}
+	}
+
+	return changes
+}
+
+// isNested reports whether a field holds other records, which are audited on their own
+func isNested(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Ptr && t.Elem().Elem().Kind() == reflect.Struct
+}
+
+// auditValue returns a comparable value for a field: pointers are followed, empty strings,
+// slices and times become nil, and times are written in UTC so the same instant
+// compares equal
+func auditValue(v reflect.Value) interface{} {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.String, reflect.Slice:
+		if v.Len() == 0 {
+			return nil
+		}
+	case reflect.Struct:
+		if t, ok := v.Interface().(time.Time); ok {
+			if t.IsZero() {
+				return nil
+			}
+			return t.UTC().Format(time.RFC3339Nano)
+		}
+	}
+	return v.Interface()
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"hrms/internal/models"
+)
+
+func TestFieldChanges(t *testing.T) {
+	ist := time.FixedZone("IST", 5*3600+1800)
+	appointed := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	appointedIST := appointed.In(ist)
+	later := appointed.AddDate(1, 0, 0)
+	lastModified := int64(1700000000)
+
+	base := func() *models.Employee {
+		return &models.Employee{
+			ID:                "emp-1",
+			TenantID:          "pg.citya",
+			Code:              "EMP-1",
+			Department:        "DEPT_1",
+			Designation:       "DESIG_1",
+			IsActive:          true,
+			MobileNumber:      "9876543210",
+			DateOfAppointment: &appointed,
+		}
+	}
+	with := func(change func(e *models.Employee)) *models.Employee {
+		e := base()
+		change(e)
+		return e
+	}
+
+	tests := []struct {
+		name string
+		old  *models.Employee
+		new  *models.Employee
+		want map[string]models.FieldChange
+	}{
+		{
+			name: "no change",
+			old:  base(),
+			new:  base(),
+			want: map[string]models.FieldChange{},
+		},
+		{
+			name: "changed string",
+			old:  base(),
+			new:  with(func(e *models.Employee) { e.Designation = "DESIG_2" }),
+			want: map[string]models.FieldChange{"designation": {Old: "DESIG_1", New: "DESIG_2"}},
+		},
+		{
+			name: "changed bool",
+			old:  base(),
+			new:  with(func(e *models.Employee) { e.IsActive = false }),
+			want: map[string]models.FieldChange{"isActive": {Old: true, New: false}},
+		},
+		{
+			name: "cleared string is recorded as nil",
+			old:  base(),
+			new:  with(func(e *models.Employee) { e.Department = "" }),
+			want: map[string]models.FieldChange{"department": {Old: "DEPT_1", New: nil}},
+		},
+		{
+			name: "same instant in another zone is unchanged",
+			old:  base(),
+			new:  with(func(e *models.Employee) { e.DateOfAppointment = &appointedIST }),
+			want: map[string]models.FieldChange{},
+		},
+		{
+			name: "changed time is written in UTC",
+			old:  base(),
+			new:  with(func(e *models.Employee) { e.DateOfAppointment = &later }),
+			want: map[string]models.FieldChange{"dateOfAppointment": {
+				Old: "2020-01-01T00:00:00Z", New: "2021-01-01T00:00:00Z",
+			}},
+		},
+		{
+			name: "encrypted field is redacted",
+			old:  base(),
+			new:  with(func(e *models.Employee) { e.MobileNumber = "9000000000" }),
+			want: map[string]models.FieldChange{"mobileNumber": {Redacted: true}},
+		},
+		{
+			name: "identifiers, bookkeeping, hidden and nested fields are skipped",
+			old:  base(),
+			new: with(func(e *models.Employee) {
+				e.ID = "emp-2"
+				e.TenantID = "pg.cityb"
+				e.MobileNumberHash = "hash"
+				e.LastModifiedTime = &lastModified
+				e.Jurisdictions = []*models.Jurisdiction{{Boundary: "WARD_1"}}
+			}),
+			want: map[string]models.FieldChange{},
+		},
+		{
+			name: "create records every set field",
+			old:  nil,
+			new:  base(),
+			want: map[string]models.FieldChange{
+				"code":              {New: "EMP-1"},
+				"department":        {New: "DEPT_1"},
+				"designation":       {New: "DESIG_1"},
+				"isActive":          {Old: nil, New: true},
+				"mobileNumber":      {Redacted: true},
+				"dateOfAppointment": {New: "2020-01-01T00:00:00Z"},
+			},
+		},
+		{
+			name: "delete records every set field",
+			old:  base(),
+			new:  nil,
+			want: map[string]models.FieldChange{
+				"code":              {Old: "EMP-1"},
+				"department":        {Old: "DEPT_1"},
+				"designation":       {Old: "DESIG_1"},
+				"isActive":          {Old: true},
+				"mobileNumber":      {Redacted: true},
+				"dateOfAppointment": {Old: "2020-01-01T00:00:00Z"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fieldChanges(tt.old, tt.new)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("fieldChanges =\n  %#v\nwant\n  %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+
+	"hrms/internal/models"
+	"hrms/internal/requestctx"
+	"hrms/pkg/errors"
+)
+
+// AuditRepository defines the interface for the append-only audit log
+type AuditRepository interface {
+	// Record appends an entry, filling in the actor and time from ctx. Updates that
+	// changed no field are not recorded.
+	Record(ctx context.Context, entry *models.AuditLog) error
+
+	// Search returns a page of an employee's audit entries, newest first, along with the
+	// total match count
+	Search(ctx context.Context, criteria *models.AuditSearchCriteria) ([]*models.AuditLog, int64, error)
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+func (r *auditRepository) Record(ctx context.Context, entry *models.AuditLog) error {
+	if entry.Operation == models.AuditOperationUpdate && len(entry.Changes) == 0 {
+		return nil
+	}
+
+	entry.Actor = requestctx.ActorID(ctx)
+	entry.CreatedTime = time.Now().Unix()
+	if err := conn(ctx, r.db).Create(entry).Error; err != nil {
+		return errors.Wrap(err, "DATABASE_ERROR", "failed to record audit entry")
+	}
+	return nil
+}
+
+func (r *auditRepository) Search(ctx context.Context, criteria *models.AuditSearchCriteria) ([]*models.AuditLog, int64, error) {
+	tx := conn(ctx, r.db).Model(&models.AuditLog{}).
+		Where("employee_id = ? AND tenant_id = ?", criteria.EmployeeID, criteria.TenantID)
+
+	if criteria.Field != "" {
+		// An empty object is contained in any value, so this matches entries with the field as a key
+		field, _ := json.Marshal(map[string]struct{}{criteria.Field: {}})
+		tx = tx.Where("changes @> ?::jsonb", string(field))
+	}
+	if criteria.Actor != "" {
+		tx = tx.Where("actor = ?", criteria.Actor)
+	}
+
+	// Count all matches before pagination is applied
+	var total int64
+	if err := tx.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "DATABASE_ERROR", "failed to count audit entries")
+	}
+
+	var entries []*models.AuditLog
+	err := tx.Order("id DESC").Limit(criteria.Limit).Offset(criteria.Offset).Find(&entries).Error
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "DATABASE_ERROR", "failed to search audit entries")
+	}
+
+	return entries, total, nil
+}
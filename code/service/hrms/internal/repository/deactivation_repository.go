@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"hrms/internal/models"
+	"hrms/pkg/errors"
+)
+
+// DeactivationRepository defines the interface for employee deactivation and
+// reactivation history. The history is append-only.
+type DeactivationRepository interface {
+	// Create records a deactivation or reactivation
+	Create(ctx context.Context, record *models.StatusHistory) error
+
+	// FindByEmployeeID returns the status history of an employee, oldest first
+	FindByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.StatusHistory, error)
+}
+
+type deactivationRepository struct {
+	db *gorm.DB
+}
+
+// NewDeactivationRepository creates a new deactivation repository
+func NewDeactivationRepository(db *gorm.DB) DeactivationRepository {
+	return &deactivationRepository{
+		db: db,
+	}
+}
+
+func (r *deactivationRepository) Create(ctx context.Context, record *models.StatusHistory) error {
+	record.CreatedTime = time.Now().Unix()
+
+	tx := conn(ctx, r.db).Model(&models.StatusHistory{}).Create(record)
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to record status change")
+	}
+	return nil
+}
+
+func (r *deactivationRepository) FindByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.StatusHistory, error) {
+	var records []*models.StatusHistory
+	tx := conn(ctx, r.db).Model(&models.StatusHistory{}).
+		Where("employee_id = ? AND tenant_id = ?", employeeID, tenantID).
+		Order("created_time ASC").
+		Find(&records)
+
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to find status history by employee ID")
+	}
+	return records, nil
+}
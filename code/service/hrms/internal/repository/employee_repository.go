@@ -2,10 +2,16 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"hrms/internal/models"
+	"hrms/internal/pii"
+	"hrms/internal/requestctx"
 	"hrms/pkg/errors"
 )
 
@@ -17,16 +23,23 @@ type EmployeeRepository interface {
 	// FindByUUID finds an employee by UUID
 	FindByUUID(ctx context.Context, uuid, tenantID string) (*models.Employee, error)
 
+	// FindByUUIDAsOf finds an employee as it was at the given time
+	FindByUUIDAsOf(ctx context.Context, uuid, tenantID string, asOf time.Time) (*models.Employee, error)
+
 	FindByCode(ctx context.Context, code, tenantID string) (*models.Employee, error)
 
+	// FindByMobileNumber finds an employee by mobile number
+	FindByMobileNumber(ctx context.Context, mobileNumber, tenantID string) (*models.Employee, error)
+
 	// Update updates an existing employee
 	Update(ctx context.Context, employee *models.Employee) error
 
 	// Delete deletes an employee by ID
 	Delete(ctx context.Context, id, tenantID string) error
 
-	// Search searches for employees based on criteria
-	Search(ctx context.Context, criteria *models.EmployeeSearchCriteria) ([]*models.Employee, error)
+	// Search searches for employees based on criteria and returns the page along with the total match count.
+	// With AsOf set, the employees are searched as they were at that time.
+	Search(ctx context.Context, criteria *models.EmployeeSearchCriteria) ([]*models.Employee, int64, error)
 
 	// UpdateStatus updates the status of an employee
 	UpdateStatus(ctx context.Context, id, status, tenantID string) error
@@ -36,31 +49,86 @@ type EmployeeRepository interface {
 
 	// UpdateIsActive updates the is_active status of an employee
 	UpdateIsActive(ctx context.Context, id string, isActive bool, tenantID string) error
+
+	// Count returns employee totals matching the criteria, grouped by is_active, status,
+	// employee_type and department. Pagination and sorting are ignored.
+	Count(ctx context.Context, criteria *models.EmployeeSearchCriteria) (*models.EmployeeCountResponse, error)
+}
+
+// employeeSortColumns maps the API sort field names to database columns
+var employeeSortColumns = map[string]string{
+	"code":         "code",
+	"createdAt":    "created_time",
+	"updatedAt":    "last_modified_time",
+	"employeeType": "employee_type",
+	"status":       "status",
 }
 
 // employeeRepository implements the EmployeeRepository interface
 type employeeRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	keys  *pii.KeyRing
+	audit AuditRepository
 }
 
-// NewEmployeeRepository creates a new employee repository
-func NewEmployeeRepository(db *gorm.DB) EmployeeRepository {
+// NewEmployeeRepository creates a new employee repository. The key ring hashes
+// mobile numbers so they can be looked up while stored encrypted. Every write is
+// recorded in the audit log in the same transaction.
+func NewEmployeeRepository(db *gorm.DB, keys *pii.KeyRing, audit AuditRepository) EmployeeRepository {
 	return &employeeRepository{
-		db: db,
+		db:    db,
+		keys:  keys,
+		audit: audit,
 	}
 }
 
 func (r *employeeRepository) Create(ctx context.Context, employee *models.Employee) error {
-	tx := r.db.WithContext(ctx).Table(models.Employee{}.TableName()).Create(employee)
+	employee.MobileNumberHash = r.keys.Hash(employee.MobileNumber)
+	employee.CreatedBy = requestctx.ActorID(ctx)
+
+	return inTx(ctx, r.db, func(ctx context.Context) error {
+		tx := conn(ctx, r.db).Table(models.Employee{}.TableName()).Create(employee)
+		if tx.Error != nil {
+			return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to create employee")
+		}
+		return r.record(ctx, models.AuditOperationCreate, nil, employee)
+	})
+}
+
+// lock loads an employee and locks its row until the transaction of ctx ends
+func (r *employeeRepository) lock(ctx context.Context, id, tenantID string) (*models.Employee, error) {
+	var employee models.Employee
+	tx := conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		First(&employee)
 	if tx.Error != nil {
-		return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to create employee")
+		if tx.Error == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotFound.WithDescription("employee not found")
+		}
+		return nil, errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to find employee by ID")
+	}
+	return &employee, nil
+}
+
+// record appends the change from old to new to the audit log
+func (r *employeeRepository) record(ctx context.Context, operation string, old, new *models.Employee) error {
+	current := new
+	if current == nil {
+		current = old
 	}
-	return nil
+	return r.audit.Record(ctx, &models.AuditLog{
+		EntityType: models.AuditEntityEmployee,
+		EntityID:   current.ID,
+		EmployeeID: current.ID,
+		Operation:  operation,
+		Changes:    fieldChanges(old, new),
+		TenantID:   current.TenantID,
+	})
 }
 
 func (r *employeeRepository) FindByCode(ctx context.Context, code, tenantID string) (*models.Employee, error) {
 	var employee models.Employee
-	tx := r.db.WithContext(ctx).Where("code = ? AND tenant_id = ?", code, tenantID).First(&employee)
+	tx := conn(ctx, r.db).Where("code = ? AND tenant_id = ?", code, tenantID).First(&employee)
 	if tx.Error != nil {
 		if tx.Error == gorm.ErrRecordNotFound {
 			return nil, errors.ErrNotFound.WithDescription("employee not found")
@@ -70,74 +138,108 @@ func (r *employeeRepository) FindByCode(ctx context.Context, code, tenantID stri
 	return &employee, nil
 }
 
-func (r *employeeRepository) FindByUUID(ctx context.Context, uuid, tenantID string) (*models.Employee, error) {
+func (r *employeeRepository) FindByMobileNumber(ctx context.Context, mobileNumber, tenantID string) (*models.Employee, error) {
 	var employee models.Employee
-	tx := r.db.WithContext(ctx).Where("id = ? AND tenant_id = ?", uuid, tenantID).First(&employee)
+	tx := conn(ctx, r.db).Where("mobile_number_hash = ? AND tenant_id = ?", r.keys.Hash(mobileNumber), tenantID).First(&employee)
 	if tx.Error != nil {
 		if tx.Error == gorm.ErrRecordNotFound {
 			return nil, errors.ErrNotFound.WithDescription("employee not found")
 		}
-		return nil, errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to find employee by ID")
+		return nil, errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to find employee by mobile number")
 	}
 	return &employee, nil
 }
 
-func (r *employeeRepository) Update(ctx context.Context, employee *models.Employee) error {
-	tx := r.db.WithContext(ctx).Model(&models.Employee{}).Where("id = ?", employee.ID).Updates(employee)
+func (r *employeeRepository) FindByUUID(ctx context.Context, uuid, tenantID string) (*models.Employee, error) {
+	var employee models.Employee
+	tx := conn(ctx, r.db).Where("id = ? AND tenant_id = ?", uuid, tenantID).First(&employee)
 	if tx.Error != nil {
-		return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to update employee")
-	}
-	if tx.RowsAffected == 0 {
-		return errors.ErrNotFound.WithDescription("employee not found")
+		if tx.Error == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotFound.WithDescription("employee not found")
+		}
+		return nil, errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to find employee by ID")
 	}
-	return nil
+	return &employee, nil
 }
 
-func (r *employeeRepository) Delete(ctx context.Context, id, tenantID string) error {
-	tx := r.db.WithContext(ctx).Where("id = ? AND tenant_id = ?", id, tenantID).Delete(&models.Employee{})
+func (r *employeeRepository) FindByUUIDAsOf(ctx context.Context, uuid, tenantID string, asOf time.Time) (*models.Employee, error) {
+	var employee models.Employee
+	db := conn(ctx, r.db)
+	tx := db.Table("(?) AS e", historyAsOf(db, models.Employee{}.TableName(), tenantID, asOf).Where("h.id = ?", uuid)).
+		Take(&employee)
 	if tx.Error != nil {
-		return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to delete employee")
-	}
-	if tx.RowsAffected == 0 {
-		return errors.ErrNotFound.WithDescription("employee not found")
+		if tx.Error == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotFound.WithDescription("employee not found")
+		}
+		return nil, errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to find employee by ID")
 	}
-	return nil
+	return &employee, nil
 }
 
-func (r *employeeRepository) Search(ctx context.Context, criteria *models.EmployeeSearchCriteria) ([]*models.Employee, error) {
-	var employees []*models.Employee
-
-	tx := r.db.WithContext(ctx).Model(&models.Employee{}).Where("tenant_id = ?", criteria.TenantID)
+// employees returns the query the search filters apply to: the employee table, or its
+// rows at criteria.AsOf rebuilt from history under the same name
+func (r *employeeRepository) employees(ctx context.Context, criteria *models.EmployeeSearchCriteria) *gorm.DB {
+	db := conn(ctx, r.db)
+	if criteria.AsOf == nil {
+		return db.Model(&models.Employee{})
+	}
+	table := models.Employee{}.TableName()
+	return db.Model(&models.Employee{}).Table("(?) AS "+table, historyAsOf(db, table, criteria.TenantID, *criteria.AsOf))
+}
 
-	// Apply filters
+func (r *employeeRepository) Update(ctx context.Context, employee *models.Employee) error {
+	employee.MobileNumberHash = r.keys.Hash(employee.MobileNumber)
+	actor := requestctx.ActorID(ctx)
+	employee.LastModifiedBy = &actor
+
+	return inTx(ctx, r.db, func(ctx context.Context) error {
+		old, err := r.lock(ctx, employee.ID, employee.TenantID)
+		if err != nil {
+			return err
+		}
 
-	if len(criteria.UUIDs) > 0 {
-		tx = tx.Where("id IN ?", criteria.UUIDs)
-	}
+		// Select all columns so that cleared fields and isActive=false are persisted
+		tx := conn(ctx, r.db).Model(&models.Employee{}).
+			Where("id = ? AND tenant_id = ?", employee.ID, employee.TenantID).
+			Select("*").
+			Omit("id", "tenant_id", "created_by", "created_time", "Jurisdictions").
+			Updates(employee)
+		if tx.Error != nil {
+			return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to update employee")
+		}
+		return r.record(ctx, models.AuditOperationUpdate, old, employee)
+	})
+}
 
-	if len(criteria.Codes) > 0 {
-		tx = tx.Where("code IN ?", criteria.Codes)
-	}
+func (r *employeeRepository) Delete(ctx context.Context, id, tenantID string) error {
+	return inTx(ctx, r.db, func(ctx context.Context) error {
+		old, err := r.lock(ctx, id, tenantID)
+		if err != nil {
+			return err
+		}
 
-	if len(criteria.Departments) > 0 {
-		tx = tx.Where("department_id IN ?", criteria.Departments)
-	}
+		tx := conn(ctx, r.db).Where("id = ? AND tenant_id = ?", id, tenantID).Delete(&models.Employee{})
+		if tx.Error != nil {
+			return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to delete employee")
+		}
+		return r.record(ctx, models.AuditOperationDelete, old, nil)
+	})
+}
 
-	if len(criteria.Designations) > 0 {
-		tx = tx.Where("designation_id IN ?", criteria.Designations)
-	}
+func (r *employeeRepository) Search(ctx context.Context, criteria *models.EmployeeSearchCriteria) ([]*models.Employee, int64, error) {
+	var employees []*models.Employee
+	var total int64
 
-	if criteria.Phone != "" {
-		tx = tx.Where("mobile_number = ?", criteria.Phone)
-	}
+	tx := applyEmployeeFilters(r.employees(ctx, criteria), criteria, r.keys)
 
-	if criteria.IsActive != nil {
-		tx = tx.Where("is_active = ?", *criteria.IsActive)
+	// Count all matches before pagination is applied
+	if err := tx.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "DATABASE_ERROR", "failed to count employees")
 	}
 
 	// Apply sorting
-	orderBy := criteria.SortBy
-	if orderBy == "" {
+	orderBy, ok := employeeSortColumns[criteria.SortBy]
+	if !ok {
 		orderBy = "created_time"
 	}
 
@@ -161,32 +263,21 @@ func (r *employeeRepository) Search(ctx context.Context, criteria *models.Employ
 	// Execute query
 	tx = tx.Find(&employees)
 	if tx.Error != nil {
-		return nil, errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to search employees")
+		return nil, 0, errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to search employees")
 	}
 
-	return employees, nil
+	return employees, total, nil
 }
 
 func (r *employeeRepository) UpdateStatus(ctx context.Context, id, status, tenantID string) error {
-	err := r.db.WithContext(ctx).Model(&models.Employee{}).
-		Where("id = ? AND tenant_id = ?", id, tenantID).
-		Update("status", status).Error
-
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return errors.ErrNotFound.WithDescription("employee not found")
-		}
-		return errors.Wrap(err, "DATABASE_ERROR", "failed to update employee status")
-	}
-
-	return nil
+	return r.updateColumn(ctx, id, tenantID, "status", status, func(e *models.Employee) { e.Status = status })
 }
 
 // EmployeeCodeExists checks if an employee with the given code already exists in the database
 func (r *employeeRepository) EmployeeCodeExists(ctx context.Context, code, tenantID string) (bool, error) {
 	var count int64
 
-	err := r.db.WithContext(ctx).Model(&models.Employee{}).
+	err := conn(ctx, r.db).Model(&models.Employee{}).
 		Where("code = ? AND tenant_id = ?", code, tenantID).
 		Count(&count).Error
 
@@ -199,14 +290,155 @@ func (r *employeeRepository) EmployeeCodeExists(ctx context.Context, code, tenan
 
 // UpdateIsActive updates the is_active status of an employee
 func (r *employeeRepository) UpdateIsActive(ctx context.Context, id string, isActive bool, tenantID string) error {
-	err := r.db.WithContext(ctx).Model(&models.Employee{}).
-		Where("id = ? AND tenant_id = ?", id, tenantID).
-		Update("is_active", isActive).Error
+	return r.updateColumn(ctx, id, tenantID, "is_active", isActive, func(e *models.Employee) { e.IsActive = isActive })
+}
+
+// updateColumn sets a single column of an employee and records the change; apply makes
+// the same change to the loaded employee so it can be compared
+func (r *employeeRepository) updateColumn(ctx context.Context, id, tenantID, column string, value interface{}, apply func(*models.Employee)) error {
+	return inTx(ctx, r.db, func(ctx context.Context) error {
+		old, err := r.lock(ctx, id, tenantID)
+		if err != nil {
+			return err
+		}
+
+		err = conn(ctx, r.db).Model(&models.Employee{}).
+			Where("id = ? AND tenant_id = ?", id, tenantID).
+			Updates(modified(ctx, map[string]interface{}{column: value})).Error
+		if err != nil {
+			return errors.Wrap(err, "DATABASE_ERROR", "failed to update employee status")
+		}
+
+		updated := *old
+		apply(&updated)
+		return r.record(ctx, models.AuditOperationUpdate, old, &updated)
+	})
+}
+
+// Count returns employee totals grouped by is_active, status, employee_type and department
+func (r *employeeRepository) Count(ctx context.Context, criteria *models.EmployeeSearchCriteria) (*models.EmployeeCountResponse, error) {
+	base := applyEmployeeFilters(r.employees(ctx, criteria), criteria, r.keys)
+
+	var activity []struct {
+		IsActive bool
+		Count    int64
+	}
+	err := base.Session(&gorm.Session{}).
+		Select("is_active, COUNT(*) AS count").
+		Group("is_active").
+		Scan(&activity).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to count employees by is_active")
+	}
+
+	result := &models.EmployeeCountResponse{}
+	for _, row := range activity {
+		result.Total += row.Count
+		if row.IsActive {
+			result.Active += row.Count
+		} else {
+			result.Inactive += row.Count
+		}
+	}
+
+	if result.ByStatus, err = countEmployeesBy(base, "status"); err != nil {
+		return nil, err
+	}
+	if result.ByEmployeeType, err = countEmployeesBy(base, "employee_type"); err != nil {
+		return nil, err
+	}
+	if result.ByDepartment, err = countEmployeesBy(base, "department"); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// countEmployeesBy groups the filtered employees by a text column and counts each group.
+// column must be a trusted column name, never user input.
+func countEmployeesBy(base *gorm.DB, column string) (map[string]int64, error) {
+	var rows []struct {
+		Key   string
+		Count int64
+	}
+	err := base.Session(&gorm.Session{}).
+		Select("COALESCE(" + column + ", '') AS key, COUNT(*) AS count").
+		Group(column).
+		Scan(&rows).Error
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return errors.ErrNotFound.WithDescription("employee not found")
+		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to count employees by "+column)
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Key] += row.Count
+	}
+	return counts, nil
+}
+
+// applyEmployeeFilters applies the search criteria filters shared by Search and Count
+func applyEmployeeFilters(tx *gorm.DB, criteria *models.EmployeeSearchCriteria, keys *pii.KeyRing) *gorm.DB {
+	tx = tx.Where("tenant_id = ?", criteria.TenantID)
+
+	if len(criteria.UUIDs) > 0 {
+		tx = tx.Where("id IN ?", criteria.UUIDs)
+	}
+
+	if len(criteria.Codes) > 0 {
+		tx = tx.Where("code IN ?", criteria.Codes)
+	}
+
+	if len(criteria.Departments) > 0 {
+		tx = tx.Where("department IN ?", criteria.Departments)
+	}
+
+	if len(criteria.Designations) > 0 {
+		tx = tx.Where("designation IN ?", criteria.Designations)
+	}
+
+	if criteria.Phone != "" {
+		tx = tx.Where("mobile_number_hash = ?", keys.Hash(criteria.Phone))
+	}
+
+	if criteria.IsActive != nil {
+		tx = tx.Where("is_active = ?", *criteria.IsActive)
+	}
+
+	if len(criteria.BoundaryCodes) > 0 {
+		clause, args := boundaryClause(criteria.BoundaryCodes, criteria.IncludeDescendants)
+		if criteria.AsOf == nil {
+			tx = tx.Where("EXISTS (SELECT 1 FROM eg_hrms_jurisdiction_v3 j"+
+				" WHERE j.employee_id = eg_hrms_employee_v3.id AND j.tenant_id = eg_hrms_employee_v3.tenant_id"+
+				" AND j.is_active AND j.valid_from <= now() AND (j.valid_to IS NULL OR j.valid_to > now())"+
+				" AND ("+clause+"))", args...)
+		} else {
+			// Match the jurisdictions stored at that time that were valid then
+			asOf := *criteria.AsOf
+			jurisdictions := historyAsOf(tx, models.Jurisdiction{}.TableName(), criteria.TenantID, asOf)
+			tx = tx.Where("EXISTS (SELECT 1 FROM (?) j"+
+				" WHERE j.employee_id = eg_hrms_employee_v3.id AND j.tenant_id = eg_hrms_employee_v3.tenant_id"+
+				" AND j.is_active AND j.valid_from <= ? AND (j.valid_to IS NULL OR j.valid_to > ?)"+
+				" AND ("+clause+"))", append([]interface{}{jurisdictions, asOf, asOf}, args...)...)
+		}
+	}
+
+	return tx
+}
+
+// boundaryClause matches jurisdictions over any of the given boundaries. Descendants are
+// found through the ancestor path in boundary_relation: a jurisdiction lies below a
+// boundary when its path contains it, which the GIN index on boundary_relation serves.
+func boundaryClause(codes []string, includeDescendants bool) (string, []interface{}) {
+	clauses := []string{"j.boundary IN ?"}
+	args := []interface{}{codes}
+
+	if includeDescendants {
+		for _, code := range codes {
+			path, _ := json.Marshal([]string{code})
+			clauses = append(clauses, "j.boundary_relation @> ?::jsonb")
+			args = append(args, string(path))
 		}
-		return errors.Wrap(err, "DATABASE_ERROR", "failed to update employee status")
 	}
-	return nil
+
+	return strings.Join(clauses, " OR "), args
 }
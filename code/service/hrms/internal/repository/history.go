@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// historyAsOf rebuilds the rows of a table as they were at the given time from its
+// versioned history, which the triggers of the employee history migration keep in
+// <table>_history. The result selects the live table's columns, so it can stand in for
+// the table as a subquery.
+func historyAsOf(db *gorm.DB, table, tenantID string, asOf time.Time) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).
+		Table(table+"_history h").
+		Select("r.*").
+		Joins("CROSS JOIN LATERAL jsonb_populate_record(NULL::"+table+", h.row_data) r").
+		Where("h.tenant_id = ? AND h.valid_from <= ? AND (h.valid_to IS NULL OR h.valid_to > ?)", tenantID, asOf, asOf)
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"hrms/internal/models"
+	"hrms/internal/pii"
+)
+
+// capturedQuery is a statement built by a dry-run query
+type capturedQuery struct {
+	sql  string
+	vars []interface{}
+}
+
+// newDryRunDB returns a database that builds statements without a server, and the
+// statements it has built so far
+func newDryRunDB(t *testing.T) (*gorm.DB, func() []capturedQuery) {
+	t.Helper()
+	pii.RegisterSerializer(newTestKeyRing(t))
+
+	sqlDB, err := sql.Open("pgx", "postgres://localhost/hrms")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("gorm: %v", err)
+	}
+
+	var queries []capturedQuery
+	capture := func(tx *gorm.DB) {
+		queries = append(queries, capturedQuery{sql: tx.Statement.SQL.String(), vars: tx.Statement.Vars})
+	}
+	if err := db.Callback().Query().After("gorm:query").Register("test:capture", capture); err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+	return db, func() []capturedQuery { return queries }
+}
+
+func countVar(vars []interface{}, want time.Time) int {
+	n := 0
+	for _, v := range vars {
+		if t, ok := v.(time.Time); ok && t.Equal(want) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestAsOfReads(t *testing.T) {
+	asOf := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	const (
+		employeeHistory     = "FROM eg_hrms_employee_v3_history h CROSS JOIN LATERAL jsonb_populate_record(NULL::eg_hrms_employee_v3, h.row_data) r"
+		jurisdictionHistory = "FROM eg_hrms_jurisdiction_v3_history h CROSS JOIN LATERAL jsonb_populate_record(NULL::eg_hrms_jurisdiction_v3, h.row_data) r"
+		versionAt           = "h.tenant_id = $1 AND h.valid_from <= $2 AND (h.valid_to IS NULL OR h.valid_to > $3)"
+	)
+
+	tests := []struct {
+		name string
+		// query runs the read; the last statement it builds is checked
+		query       func(ctx context.Context, employees EmployeeRepository, jurisdictions JurisdictionRepository) error
+		wantSQL     []string
+		wantNoSQL   []string
+		wantAsOfVar int
+	}{
+		{
+			name: "employee by ID as of a time",
+			query: func(ctx context.Context, employees EmployeeRepository, _ JurisdictionRepository) error {
+				_, err := employees.FindByUUIDAsOf(ctx, "emp-1", "pg.citya", asOf)
+				return err
+			},
+			wantSQL:     []string{employeeHistory, versionAt, "AND h.id = $4) AS e"},
+			wantAsOfVar: 2,
+		},
+		{
+			name: "employee by ID now reads the live table",
+			query: func(ctx context.Context, employees EmployeeRepository, _ JurisdictionRepository) error {
+				_, err := employees.FindByUUID(ctx, "emp-1", "pg.citya")
+				return err
+			},
+			wantSQL:   []string{`FROM "eg_hrms_employee_v3" WHERE id = $1 AND tenant_id = $2`},
+			wantNoSQL: []string{"_history"},
+		},
+		{
+			name: "search as of a time reads history under the live table name",
+			query: func(ctx context.Context, employees EmployeeRepository, _ JurisdictionRepository) error {
+				_, _, err := employees.Search(ctx, &models.EmployeeSearchCriteria{TenantID: "pg.citya", AsOf: &asOf, Codes: []string{"EMP-1"}})
+				return err
+			},
+			wantSQL:     []string{employeeHistory, versionAt, ") AS eg_hrms_employee_v3 WHERE", "tenant_id = $4 AND code IN ($5)"},
+			wantAsOfVar: 2,
+		},
+		{
+			name: "search by boundary as of a time matches the jurisdictions held then",
+			query: func(ctx context.Context, employees EmployeeRepository, _ JurisdictionRepository) error {
+				_, _, err := employees.Search(ctx, &models.EmployeeSearchCriteria{TenantID: "pg.citya", AsOf: &asOf, BoundaryCodes: []string{"WARD_1"}})
+				return err
+			},
+			wantSQL:     []string{employeeHistory, jurisdictionHistory, "j.valid_from <= $", "j.valid_to > $"},
+			wantNoSQL:   []string{"now()"},
+			wantAsOfVar: 6,
+		},
+		{
+			name: "search by boundary now matches current jurisdictions",
+			query: func(ctx context.Context, employees EmployeeRepository, _ JurisdictionRepository) error {
+				_, _, err := employees.Search(ctx, &models.EmployeeSearchCriteria{TenantID: "pg.citya", BoundaryCodes: []string{"WARD_1"}})
+				return err
+			},
+			wantSQL:   []string{"FROM eg_hrms_jurisdiction_v3 j", "j.valid_from <= now()"},
+			wantNoSQL: []string{"_history"},
+		},
+		{
+			name: "jurisdictions recorded at a time",
+			query: func(ctx context.Context, _ EmployeeRepository, jurisdictions JurisdictionRepository) error {
+				_, err := jurisdictions.Search(ctx, &models.JurisdictionSearchCriteria{TenantID: "pg.citya", EmployeeID: "emp-1", RecordedAt: &asOf})
+				return err
+			},
+			wantSQL:     []string{jurisdictionHistory, versionAt, ") AS eg_hrms_jurisdiction_v3 WHERE"},
+			wantAsOfVar: 2,
+		},
+		{
+			name: "jurisdictions recorded at and valid at a time",
+			query: func(ctx context.Context, _ EmployeeRepository, jurisdictions JurisdictionRepository) error {
+				_, err := jurisdictions.Search(ctx, &models.JurisdictionSearchCriteria{TenantID: "pg.citya", EmployeeID: "emp-1", RecordedAt: &asOf, AsOf: &asOf})
+				return err
+			},
+			wantSQL:     []string{jurisdictionHistory, "valid_from <= $", "(valid_to IS NULL OR valid_to > $"},
+			wantAsOfVar: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, queries := newDryRunDB(t)
+			employees := NewEmployeeRepository(db, newTestKeyRing(t), nil)
+			jurisdictions := NewJurisdictionRepository(db, nil)
+
+			if err := tt.query(context.Background(), employees, jurisdictions); err != nil {
+				t.Fatalf("query: %v", err)
+			}
+			built := queries()
+			if len(built) == 0 {
+				t.Fatalf("no statement was built")
+			}
+			last := built[len(built)-1]
+
+			for _, want := range tt.wantSQL {
+				if !strings.Contains(last.sql, want) {
+					t.Errorf("SQL does not contain %q:\n%s", want, last.sql)
+				}
+			}
+			for _, unwanted := range tt.wantNoSQL {
+				if strings.Contains(last.sql, unwanted) {
+					t.Errorf("SQL contains %q:\n%s", unwanted, last.sql)
+				}
+			}
+			if got := countVar(last.vars, asOf); got != tt.wantAsOfVar {
+				t.Errorf("asOf is bound %d times, want %d:\n%s %v", got, tt.wantAsOfVar, last.sql, last.vars)
+			}
+		})
+	}
+}
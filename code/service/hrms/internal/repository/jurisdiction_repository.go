@@ -6,8 +6,10 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"hrms/internal/models"
+	"hrms/internal/requestctx"
 	"hrms/pkg/errors"
 )
 
@@ -17,37 +19,84 @@ type JurisdictionRepository interface {
 	FindByUUID(ctx context.Context, uuid, tenantID string) (*models.Jurisdiction, error)
 	Update(ctx context.Context, jurisdiction *models.Jurisdiction) error
 	Delete(ctx context.Context, id, tenantID string) error
+	// CloseByEmployeeID ends the jurisdictions of an employee that are still valid at the given time
+	CloseByEmployeeID(ctx context.Context, employeeID, tenantID string, at time.Time) error
 	Search(ctx context.Context, criteria *models.JurisdictionSearchCriteria) ([]*models.Jurisdiction, error)
+	// FindMissingBoundaryDetails lists, across tenants and ordered by id, up to limit
+	// jurisdictions after afterID that have a boundary but no hierarchy or boundary type
+	FindMissingBoundaryDetails(ctx context.Context, afterID string, limit int) ([]*models.Jurisdiction, error)
+	// FillBoundaryDetails sets the hierarchy and boundary type of a jurisdiction where
+	// they are still empty
+	FillBoundaryDetails(ctx context.Context, id, tenantID, hierarchy, boundaryType string) error
 }
 
 type jurisdictionRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	audit AuditRepository
 }
 
-func NewJurisdictionRepository(db *gorm.DB) JurisdictionRepository {
+// NewJurisdictionRepository creates a jurisdiction repository that records every write
+// in the audit log
+func NewJurisdictionRepository(db *gorm.DB, audit AuditRepository) JurisdictionRepository {
 	return &jurisdictionRepository{
-		db: db,
+		db:    db,
+		audit: audit,
 	}
 }
 
+// lock loads a jurisdiction and locks its row until the transaction of ctx ends
+func (r *jurisdictionRepository) lock(ctx context.Context, id, tenantID string) (*models.Jurisdiction, error) {
+	var jurisdiction models.Jurisdiction
+	tx := conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		First(&jurisdiction)
+	if tx.Error != nil {
+		if tx.Error == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotFound.WithDescription("jurisdiction not found")
+		}
+		return nil, errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to find jurisdiction")
+	}
+	return &jurisdiction, nil
+}
+
+// record appends the change from old to new to the audit log of the employee
+func (r *jurisdictionRepository) record(ctx context.Context, operation string, old, new *models.Jurisdiction) error {
+	current := new
+	if current == nil {
+		current = old
+	}
+	return r.audit.Record(ctx, &models.AuditLog{
+		EntityType: models.AuditEntityJurisdiction,
+		EntityID:   current.ID,
+		EmployeeID: current.EmployeeID,
+		Operation:  operation,
+		Changes:    fieldChanges(old, new),
+		TenantID:   current.TenantID,
+	})
+}
+
 func (r *jurisdictionRepository) Create(ctx context.Context, jurisdiction *models.Jurisdiction) error {
 	// Set timestamps
 	now := time.Now()
 	jurisdiction.CreatedTime = now.Unix()
 	lastModTime := now.Unix()
 	jurisdiction.LastModifiedTime = &lastModTime
-
-	// Create the record using GORM
-	tx := r.db.WithContext(ctx).Model(&models.Jurisdiction{}).Create(jurisdiction)
-	if tx.Error != nil {
-		return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to create jurisdiction")
-	}
-	return nil
+	actor := requestctx.ActorID(ctx)
+	jurisdiction.CreatedBy = actor
+	jurisdiction.LastModifiedBy = &actor
+
+	return inTx(ctx, r.db, func(ctx context.Context) error {
+		tx := conn(ctx, r.db).Model(&models.Jurisdiction{}).Create(jurisdiction)
+		if tx.Error != nil {
+			return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to create jurisdiction")
+		}
+		return r.record(ctx, models.AuditOperationCreate, nil, jurisdiction)
+	})
 }
 
 func (r *jurisdictionRepository) FindByID(ctx context.Context, id, tenantID string) (*models.Jurisdiction, error) {
 	var jurisdiction models.Jurisdiction
-	tx := r.db.WithContext(ctx).Model(&models.Jurisdiction{}).
+	tx := conn(ctx, r.db).Model(&models.Jurisdiction{}).
 		Where("id = ? AND tenant_id = ?", id, tenantID).
 		First(&jurisdiction)
 
@@ -63,7 +112,7 @@ func (r *jurisdictionRepository) FindByID(ctx context.Context, id, tenantID stri
 
 func (r *jurisdictionRepository) FindByUUID(ctx context.Context, uuid, tenantID string) (*models.Jurisdiction, error) {
 	var jurisdiction models.Jurisdiction
-	tx := r.db.WithContext(ctx).Model(&models.Jurisdiction{}).
+	tx := conn(ctx, r.db).Model(&models.Jurisdiction{}).
 		Where("id = ? AND tenant_id = ?", uuid, tenantID).
 		First(&jurisdiction)
 
@@ -81,36 +130,102 @@ func (r *jurisdictionRepository) Update(ctx context.Context, jurisdiction *model
 	now := time.Now()
 	lastModTime := now.Unix()
 	jurisdiction.LastModifiedTime = &lastModTime
-	tx := r.db.WithContext(ctx).Model(&models.Jurisdiction{}).Where("id = ?", jurisdiction.ID).Updates(jurisdiction)
-	if tx.Error != nil {
-		return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to update jurisdiction")
-	}
-	if tx.RowsAffected == 0 {
-		return errors.ErrNotFound.WithDescription("jurisdiction not found")
-	}
-	return nil
+	actor := requestctx.ActorID(ctx)
+	jurisdiction.LastModifiedBy = &actor
+
+	return inTx(ctx, r.db, func(ctx context.Context) error {
+		old, err := r.lock(ctx, jurisdiction.ID, jurisdiction.TenantID)
+		if err != nil {
+			return err
+		}
+
+		// Select all columns so cleared boundary details are written too
+		tx := conn(ctx, r.db).Model(&models.Jurisdiction{}).
+			Where("id = ? AND tenant_id = ?", jurisdiction.ID, jurisdiction.TenantID).
+			Select("*").Omit("id", "tenant_id", "created_by", "created_time").
+			Updates(jurisdiction)
+		if tx.Error != nil {
+			return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to update jurisdiction")
+		}
+		return r.record(ctx, models.AuditOperationUpdate, old, jurisdiction)
+	})
 }
 
 func (r *jurisdictionRepository) Delete(ctx context.Context, id, tenantID string) error {
-	tx := r.db.WithContext(ctx).
-		Where("id = ? AND tenant_id = ?", id, tenantID).
-		Delete(&models.Jurisdiction{})
+	return inTx(ctx, r.db, func(ctx context.Context) error {
+		old, err := r.lock(ctx, id, tenantID)
+		if err != nil {
+			return err
+		}
 
-	if tx.Error != nil {
-		return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to delete jurisdiction")
-	}
+		tx := conn(ctx, r.db).
+			Where("id = ? AND tenant_id = ?", id, tenantID).
+			Delete(&models.Jurisdiction{})
+		if tx.Error != nil {
+			return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to delete jurisdiction")
+		}
+		return r.record(ctx, models.AuditOperationDelete, old, nil)
+	})
+}
 
-	if tx.RowsAffected == 0 {
-		return errors.ErrNotFound.WithDescription("jurisdiction not found")
-	}
+// CloseByEmployeeID sets valid_to on the jurisdictions of an employee that are still
+// valid at the given time and deactivates them. Jurisdictions that would only start after
+// it never took effect and are deleted.
+func (r *jurisdictionRepository) CloseByEmployeeID(ctx context.Context, employeeID, tenantID string, at time.Time) error {
+	return inTx(ctx, r.db, func(ctx context.Context) error {
+		db := conn(ctx, r.db)
+
+		// Close the rows one at a time so each gets its own audit entry
+		var open []*models.Jurisdiction
+		tx := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("employee_id = ? AND tenant_id = ? AND (valid_to IS NULL OR valid_to > ?)", employeeID, tenantID, at).
+			Find(&open)
+		if tx.Error != nil {
+			return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to find jurisdictions to close")
+		}
 
-	return nil
+		for _, old := range open {
+			if old.ValidFrom != nil && !old.ValidFrom.Before(at) {
+				tx = db.Where("id = ? AND tenant_id = ?", old.ID, tenantID).Delete(&models.Jurisdiction{})
+				if tx.Error != nil {
+					return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to delete future jurisdictions")
+				}
+				if err := r.record(ctx, models.AuditOperationDelete, old, nil); err != nil {
+					return err
+				}
+				continue
+			}
+
+			tx = db.Model(&models.Jurisdiction{}).
+				Where("id = ? AND tenant_id = ?", old.ID, tenantID).
+				Updates(modified(ctx, map[string]interface{}{
+					"valid_to":  at,
+					"is_active": false,
+				}))
+			if tx.Error != nil {
+				return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to close jurisdictions")
+			}
+
+			closed := *old
+			validTo := at
+			closed.ValidTo = &validTo
+			closed.IsActive = false
+			if err := r.record(ctx, models.AuditOperationUpdate, old, &closed); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (r *jurisdictionRepository) Search(ctx context.Context, criteria *models.JurisdictionSearchCriteria) ([]*models.Jurisdiction, error) {
 	var jurisdictions []*models.Jurisdiction
 
-	tx := r.db.WithContext(ctx).Model(&models.Jurisdiction{})
+	tx := conn(ctx, r.db).Model(&models.Jurisdiction{})
+	if criteria.RecordedAt != nil {
+		table := models.Jurisdiction{}.TableName()
+		tx = tx.Table("(?) AS "+table, historyAsOf(tx, table, criteria.TenantID, *criteria.RecordedAt))
+	}
 
 	if criteria.TenantID != "" {
 		tx = tx.Where("tenant_id = ?", criteria.TenantID)
@@ -132,6 +247,10 @@ func (r *jurisdictionRepository) Search(ctx context.Context, criteria *models.Ju
 		tx = tx.Where("is_active = ?", *criteria.IsActive)
 	}
 
+	if criteria.AsOf != nil {
+		tx = tx.Where("valid_from <= ? AND (valid_to IS NULL OR valid_to > ?)", *criteria.AsOf, *criteria.AsOf)
+	}
+
 	// Apply pagination
 	if criteria.Limit > 0 {
 		tx = tx.Limit(criteria.Limit)
@@ -159,7 +278,7 @@ func (r *jurisdictionRepository) Search(ctx context.Context, criteria *models.Ju
 
 func (r *jurisdictionRepository) FindByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.Jurisdiction, error) {
 	var jurisdictions []*models.Jurisdiction
-	tx := r.db.WithContext(ctx).Model(&models.Jurisdiction{}).
+	tx := conn(ctx, r.db).Model(&models.Jurisdiction{}).
 		Where("employee_id = ? AND tenant_id = ?", employeeID, tenantID).
 		Find(&jurisdictions)
 
@@ -169,3 +288,52 @@ func (r *jurisdictionRepository) FindByEmployeeID(ctx context.Context, employeeI
 
 	return jurisdictions, nil
 }
+
+func (r *jurisdictionRepository) FindMissingBoundaryDetails(ctx context.Context, afterID string, limit int) ([]*models.Jurisdiction, error) {
+	var jurisdictions []*models.Jurisdiction
+	tx := conn(ctx, r.db).Model(&models.Jurisdiction{}).
+		Where("id > ?", afterID).
+		Where("COALESCE(boundary, '') <> ''").
+		Where("COALESCE(hierarchy, '') = '' OR COALESCE(boundary_type, '') = ''").
+		Order("id").Limit(limit).
+		Find(&jurisdictions)
+
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to find jurisdictions without boundary details")
+	}
+
+	return jurisdictions, nil
+}
+
+// FillBoundaryDetails re-reads the jurisdiction under a row lock so details written by a
+// concurrent update are kept, and only fills the ones that are still empty
+func (r *jurisdictionRepository) FillBoundaryDetails(ctx context.Context, id, tenantID, hierarchy, boundaryType string) error {
+	return inTx(ctx, r.db, func(ctx context.Context) error {
+		old, err := r.lock(ctx, id, tenantID)
+		if err != nil {
+			return err
+		}
+
+		filled := *old
+		columns := map[string]interface{}{}
+		if filled.Hierarchy == "" && hierarchy != "" {
+			filled.Hierarchy = hierarchy
+			columns["hierarchy"] = hierarchy
+		}
+		if filled.BoundaryType == "" && boundaryType != "" {
+			filled.BoundaryType = boundaryType
+			columns["boundary_type"] = boundaryType
+		}
+		if len(columns) == 0 {
+			return nil
+		}
+
+		tx := conn(ctx, r.db).Model(&models.Jurisdiction{}).
+			Where("id = ? AND tenant_id = ?", id, tenantID).
+			Updates(modified(ctx, columns))
+		if tx.Error != nil {
+			return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to fill jurisdiction boundary details")
+		}
+		return r.record(ctx, models.AuditOperationUpdate, old, &filled)
+	})
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"hrms/internal/models"
+)
+
+// recordingAudit keeps audit entries in memory
+type recordingAudit struct {
+	AuditRepository
+	entries []*models.AuditLog
+}
+
+func (a *recordingAudit) Record(ctx context.Context, entry *models.AuditLog) error {
+	a.entries = append(a.entries, entry)
+	return nil
+}
+
+// serveJurisdictions makes dry-run reads into a jurisdiction slice return rows, and
+// returns the delete and update statements built so far
+func serveJurisdictions(t *testing.T, db *gorm.DB, rows []*models.Jurisdiction) func() []capturedQuery {
+	t.Helper()
+
+	serve := func(tx *gorm.DB) {
+		dest, ok := tx.Statement.Dest.(*[]*models.Jurisdiction)
+		if !ok {
+			return
+		}
+		for _, row := range rows {
+			copied := *row
+			*dest = append(*dest, &copied)
+		}
+	}
+	if err := db.Callback().Query().After("gorm:query").Register("test:serve", serve); err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+
+	var writes []capturedQuery
+	capture := func(tx *gorm.DB) {
+		writes = append(writes, capturedQuery{sql: tx.Statement.SQL.String(), vars: tx.Statement.Vars})
+	}
+	if err := db.Callback().Delete().After("gorm:delete").Register("test:capture", capture); err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+	if err := db.Callback().Update().After("gorm:update").Register("test:capture", capture); err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+	return func() []capturedQuery { return writes }
+}
+
+func TestCloseByEmployeeID(t *testing.T) {
+	at := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	before := at.AddDate(0, -1, 0)
+	after := at.AddDate(0, 1, 0)
+	later := at.AddDate(0, 2, 0)
+
+	row := func(id string, from, to *time.Time) *models.Jurisdiction {
+		return &models.Jurisdiction{ID: id, EmployeeID: "emp-1", Boundary: "WARD-1", IsActive: true, ValidFrom: from, ValidTo: to, TenantID: "pg.citya"}
+	}
+
+	tests := []struct {
+		name string
+		rows []*models.Jurisdiction
+		// want lists the write for each row in order, as "close <id>" or "delete <id>"
+		want []string
+	}{
+		{name: "nothing open", want: nil},
+		{name: "current row is closed", rows: []*models.Jurisdiction{row("j-1", &before, nil)}, want: []string{"close j-1"}},
+		{name: "row without a start is closed", rows: []*models.Jurisdiction{row("j-1", nil, nil)}, want: []string{"close j-1"}},
+		{name: "row ending later is closed", rows: []*models.Jurisdiction{row("j-1", &before, &later)}, want: []string{"close j-1"}},
+		{name: "row starting at the close is deleted", rows: []*models.Jurisdiction{row("j-1", &at, nil)}, want: []string{"delete j-1"}},
+		{name: "future row is deleted", rows: []*models.Jurisdiction{row("j-1", &after, &later)}, want: []string{"delete j-1"}},
+		{
+			name: "current and future rows",
+			rows: []*models.Jurisdiction{row("j-1", &before, &after), row("j-2", &after, nil), row("j-3", nil, nil)},
+			want: []string{"close j-1", "delete j-2", "close j-3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, queries := newDryRunDB(t)
+			writes := serveJurisdictions(t, db, tt.rows)
+			audit := &recordingAudit{}
+			repo := NewJurisdictionRepository(db, audit)
+
+			// Join a transaction so the dry run never begins one on the server
+			ctx := context.WithValue(context.Background(), txKey{}, db)
+			if err := repo.CloseByEmployeeID(ctx, "emp-1", "pg.citya", at); err != nil {
+				t.Fatalf("CloseByEmployeeID: %v", err)
+			}
+
+			reads := queries()
+			if len(reads) != 1 {
+				t.Fatalf("got %d reads, want 1", len(reads))
+			}
+			if !strings.Contains(reads[0].sql, "valid_to IS NULL OR valid_to >") || !strings.HasSuffix(reads[0].sql, "FOR UPDATE") {
+				t.Fatalf("open rows read as %q, want open rows locked for update", reads[0].sql)
+			}
+			if countVar(reads[0].vars, at) != 1 {
+				t.Fatalf("open rows read with %v, want the close time", reads[0].vars)
+			}
+
+			built := writes()
+			if len(built) != len(tt.want) || len(audit.entries) != len(tt.want) {
+				t.Fatalf("got %d writes and %d audit entries, want %d of each", len(built), len(audit.entries), len(tt.want))
+			}
+			for i, want := range tt.want {
+				op, id, _ := strings.Cut(want, " ")
+				entry := audit.entries[i]
+				if entry.EntityID != id {
+					t.Fatalf("audit entry %d is for %s, want %s", i, entry.EntityID, id)
+				}
+
+				switch op {
+				case "close":
+					if !strings.HasPrefix(built[i].sql, "UPDATE") || countVar(built[i].vars, at) != 1 {
+						t.Fatalf("write %d = %q %v, want valid_to set to the close time", i, built[i].sql, built[i].vars)
+					}
+					if entry.Operation != models.AuditOperationUpdate {
+						t.Fatalf("audit entry %d is %s, want %s", i, entry.Operation, models.AuditOperationUpdate)
+					}
+					if _, ok := entry.Changes["validTo"]; !ok {
+						t.Fatalf("audit entry %d changes %v, want validTo", i, entry.Changes)
+					}
+				case "delete":
+					if !strings.HasPrefix(built[i].sql, "DELETE") {
+						t.Fatalf("write %d = %q, want a delete", i, built[i].sql)
+					}
+					if entry.Operation != models.AuditOperationDelete {
+						t.Fatalf("audit entry %d is %s, want %s", i, entry.Operation, models.AuditOperationDelete)
+					}
+				}
+				if !containsVar(built[i].vars, id) {
+					t.Fatalf("write %d = %v, want it scoped to %s", i, built[i].vars, id)
+				}
+			}
+		})
+	}
+}
+
+func containsVar(vars []interface{}, want string) bool {
+	for _, v := range vars {
+		if s, ok := v.(string); ok && s == want {
+			return true
+		}
+	}
+	return false
+}
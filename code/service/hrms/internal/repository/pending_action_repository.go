@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"hrms/internal/models"
+	"hrms/pkg/errors"
+)
+
+// PendingActionRepository defines the interface for scheduled status change data access operations
+type PendingActionRepository interface {
+	// Create stores a new pending action
+	Create(ctx context.Context, action *models.PendingStatusAction) error
+
+	// FindByUUID finds a pending action by UUID
+	FindByUUID(ctx context.Context, uuid, tenantID string) (*models.PendingStatusAction, error)
+
+	// FindByEmployeeID returns the actions of an employee, optionally filtered by status
+	FindByEmployeeID(ctx context.Context, employeeID, status, tenantID string) ([]*models.PendingStatusAction, error)
+
+	// FindDue returns pending actions across all tenants whose effective time has passed
+	FindDue(ctx context.Context, now time.Time, limit int) ([]*models.PendingStatusAction, error)
+
+	// ClaimPending locks a pending action until the transaction of ctx ends. It returns nil
+	// when the action is no longer pending or another instance holds it.
+	ClaimPending(ctx context.Context, id string) (*models.PendingStatusAction, error)
+
+	// TransitionStatus moves an action from one status to another. It returns false when the
+	// action is no longer in the expected status, e.g. because another instance claimed it.
+	TransitionStatus(ctx context.Context, id, from, to, failureReason string) (bool, error)
+}
+
+type pendingActionRepository struct {
+	db *gorm.DB
+}
+
+// NewPendingActionRepository creates a new pending action repository
+func NewPendingActionRepository(db *gorm.DB) PendingActionRepository {
+	return &pendingActionRepository{
+		db: db,
+	}
+}
+
+func (r *pendingActionRepository) Create(ctx context.Context, action *models.PendingStatusAction) error {
+	now := time.Now().Unix()
+	action.CreatedTime = now
+	action.LastModifiedTime = &now
+
+	tx := conn(ctx, r.db).Model(&models.PendingStatusAction{}).Create(action)
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to create pending action")
+	}
+	return nil
+}
+
+func (r *pendingActionRepository) FindByUUID(ctx context.Context, uuid, tenantID string) (*models.PendingStatusAction, error) {
+	var action models.PendingStatusAction
+	tx := conn(ctx, r.db).Model(&models.PendingStatusAction{}).
+		Where("id = ? AND tenant_id = ?", uuid, tenantID).
+		First(&action)
+
+	if tx.Error != nil {
+		if tx.Error == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotFound.WithDescription("pending action not found")
+		}
+		return nil, errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to find pending action")
+	}
+	return &action, nil
+}
+
+func (r *pendingActionRepository) FindByEmployeeID(ctx context.Context, employeeID, status, tenantID string) ([]*models.PendingStatusAction, error) {
+	var actions []*models.PendingStatusAction
+	tx := conn(ctx, r.db).Model(&models.PendingStatusAction{}).
+		Where("employee_id = ? AND tenant_id = ?", employeeID, tenantID)
+
+	if status != "" {
+		tx = tx.Where("status = ?", status)
+	}
+
+	tx = tx.Order("effective_from ASC").Find(&actions)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to find pending actions by employee ID")
+	}
+	return actions, nil
+}
+
+func (r *pendingActionRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]*models.PendingStatusAction, error) {
+	var actions []*models.PendingStatusAction
+	tx := conn(ctx, r.db).Model(&models.PendingStatusAction{}).
+		Where("status = ? AND effective_from <= ?", models.PendingActionStatusPending, now).
+		Order("effective_from ASC")
+
+	if limit > 0 {
+		tx = tx.Limit(limit)
+	}
+
+	tx = tx.Find(&actions)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to find due pending actions")
+	}
+	return actions, nil
+}
+
+func (r *pendingActionRepository) ClaimPending(ctx context.Context, id string) (*models.PendingStatusAction, error) {
+	var actions []*models.PendingStatusAction
+	tx := conn(ctx, r.db).Model(&models.PendingStatusAction{}).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("id = ? AND status = ?", id, models.PendingActionStatusPending).
+		Limit(1).
+		Find(&actions)
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to claim pending action")
+	}
+	if len(actions) == 0 {
+		return nil, nil
+	}
+	return actions[0], nil
+}
+
+func (r *pendingActionRepository) TransitionStatus(ctx context.Context, id, from, to, failureReason string) (bool, error) {
+	tx := conn(ctx, r.db).Model(&models.PendingStatusAction{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(map[string]interface{}{
+			"status":             to,
+			"failure_reason":     failureReason,
+			"last_modified_time": time.Now().Unix(),
+		})
+
+	if tx.Error != nil {
+		return false, errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to update pending action status")
+	}
+	return tx.RowsAffected > 0, nil
+}
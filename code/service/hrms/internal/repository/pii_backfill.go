@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"hrms/internal/models"
+	"hrms/internal/pii"
+	"hrms/pkg/errors"
+)
+
+// piiBackfillBatchSize caps how many rows are read per backfill query
+const piiBackfillBatchSize = 500
+
+// encryptedColumns are the employee columns stored through the encrypted serializer
+var encryptedColumns = []string{"mobile_number", "email", "pan_number", "aadhaar_number"}
+
+// PIIBackfillResult reports the outcome of BackfillPII
+type PIIBackfillResult struct {
+	// Employees is the number of employee rows rewritten
+	Employees int
+	// Versions is the number of employee history versions rewritten
+	Versions int
+	// DuplicateMobile lists employees whose mobile number is already held by another
+	// employee of the tenant. They are encrypted but left without a lookup hash until
+	// the duplicate is resolved.
+	DuplicateMobile []string
+}
+
+// piiRow is an employee row read without the encrypted serializer
+type piiRow struct {
+	ID               string
+	TenantID         string
+	MobileNumber     string
+	Email            string
+	PanNumber        string
+	AadhaarNumber    string
+	MobileNumberHash string
+}
+
+// BackfillPII encrypts employee personal identifiers written before encryption was
+// introduced and fills in missing mobile number hashes, in the employee table and in its
+// history. Rows are read without the encrypted serializer, which rejects plaintext. It
+// is safe to run repeatedly; rows that are already encrypted and hashed are skipped.
+func BackfillPII(ctx context.Context, db *gorm.DB, keys *pii.KeyRing) (*PIIBackfillResult, error) {
+	result := &PIIBackfillResult{}
+	if err := backfillEmployees(ctx, db, keys, result); err != nil {
+		return nil, err
+	}
+	if err := backfillEmployeeHistory(ctx, db, keys, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func backfillEmployees(ctx context.Context, db *gorm.DB, keys *pii.KeyRing, result *PIIBackfillResult) error {
+	table := models.Employee{}.TableName()
+	pending := []string{"(mobile_number <> '' AND (mobile_number_hash IS NULL OR mobile_number_hash = ''))"}
+	for _, column := range encryptedColumns {
+		pending = append(pending, "("+column+" <> '' AND "+column+" NOT LIKE 'enc:%')")
+	}
+
+	// Walk the table by id so rows that cannot be hashed are not read again
+	lastID := "00000000-0000-0000-0000-000000000000"
+	for {
+		var rows []piiRow
+		err := db.WithContext(ctx).Table(table).
+			Select("id, tenant_id, COALESCE(mobile_number, '') AS mobile_number, COALESCE(email, '') AS email, "+
+				"COALESCE(pan_number, '') AS pan_number, COALESCE(aadhaar_number, '') AS aadhaar_number, "+
+				"COALESCE(mobile_number_hash, '') AS mobile_number_hash").
+			Where("id > ?", lastID).
+			Where(strings.Join(pending, " OR ")).
+			Order("id").Limit(piiBackfillBatchSize).
+			Scan(&rows).Error
+		if err != nil {
+			return errors.Wrap(err, "DATABASE_ERROR", "failed to read employees to encrypt")
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		for _, row := range rows {
+			lastID = row.ID
+			columns, err := encryptRow(keys, map[string]string{
+				"mobile_number":  row.MobileNumber,
+				"email":          row.Email,
+				"pan_number":     row.PanNumber,
+				"aadhaar_number": row.AadhaarNumber,
+			})
+			if err != nil {
+				return errors.Wrap(err, "INTERNAL_ERROR", "failed to encrypt employee "+row.ID)
+			}
+
+			if row.MobileNumberHash == "" && row.MobileNumber != "" {
+				hash, err := mobileHash(keys, row.MobileNumber)
+				if err != nil {
+					return errors.Wrap(err, "INTERNAL_ERROR", "failed to hash mobile number of employee "+row.ID)
+				}
+
+				var taken int64
+				err = db.WithContext(ctx).Table(table).
+					Where("mobile_number_hash = ? AND tenant_id = ? AND id <> ?", hash, row.TenantID, row.ID).
+					Count(&taken).Error
+				if err != nil {
+					return errors.Wrap(err, "DATABASE_ERROR", "failed to check mobile number uniqueness")
+				}
+				if taken > 0 {
+					result.DuplicateMobile = append(result.DuplicateMobile, row.ID)
+				} else {
+					columns["mobile_number_hash"] = hash
+				}
+			}
+
+			if len(columns) == 0 {
+				continue
+			}
+			err = db.WithContext(ctx).Table(table).Where("id = ?", row.ID).UpdateColumns(columns).Error
+			if err != nil {
+				return errors.Wrap(err, "DATABASE_ERROR", "failed to encrypt employee "+row.ID)
+			}
+			result.Employees++
+		}
+	}
+}
+
+func backfillEmployeeHistory(ctx context.Context, db *gorm.DB, keys *pii.KeyRing, result *PIIBackfillResult) error {
+	table := models.Employee{}.TableName() + "_history"
+	pending := make([]string, 0, len(encryptedColumns))
+	for _, column := range encryptedColumns {
+		pending = append(pending, "(row_data->>'"+column+"' <> '' AND row_data->>'"+column+"' NOT LIKE 'enc:%')")
+	}
+
+	var lastID int64
+	for {
+		var rows []struct {
+			HistoryID int64
+			RowData   string
+		}
+		err := db.WithContext(ctx).Table(table).
+			Select("history_id, row_data::text AS row_data").
+			Where("history_id > ?", lastID).
+			Where(strings.Join(pending, " OR ")).
+			Order("history_id").Limit(piiBackfillBatchSize).
+			Scan(&rows).Error
+		if err != nil {
+			return errors.Wrap(err, "DATABASE_ERROR", "failed to read employee history to encrypt")
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		for _, row := range rows {
+			lastID = row.HistoryID
+
+			var data map[string]interface{}
+			if err := json.Unmarshal([]byte(row.RowData), &data); err != nil {
+				return errors.Wrap(err, "INTERNAL_ERROR", "failed to read employee history")
+			}
+			values := make(map[string]string, len(encryptedColumns))
+			for _, column := range encryptedColumns {
+				if v, ok := data[column].(string); ok {
+					values[column] = v
+				}
+			}
+
+			columns, err := encryptRow(keys, values)
+			if err != nil {
+				return errors.Wrap(err, "INTERNAL_ERROR", "failed to encrypt employee history")
+			}
+			if mobile := values["mobile_number"]; mobile != "" && data["mobile_number_hash"] == nil {
+				if columns["mobile_number_hash"], err = mobileHash(keys, mobile); err != nil {
+					return errors.Wrap(err, "INTERNAL_ERROR", "failed to hash mobile number in employee history")
+				}
+			}
+			for column, value := range columns {
+				data[column] = value
+			}
+
+			encoded, err := json.Marshal(data)
+			if err != nil {
+				return errors.Wrap(err, "INTERNAL_ERROR", "failed to write employee history")
+			}
+			err = db.WithContext(ctx).Table(table).Where("history_id = ?", row.HistoryID).
+				UpdateColumn("row_data", gorm.Expr("?::jsonb", string(encoded))).Error
+			if err != nil {
+				return errors.Wrap(err, "DATABASE_ERROR", "failed to encrypt employee history")
+			}
+			result.Versions++
+		}
+	}
+}
+
+// encryptRow returns the encrypted value of every non-empty column that is still plaintext
+func encryptRow(keys *pii.KeyRing, values map[string]string) (map[string]interface{}, error) {
+	columns := make(map[string]interface{})
+	for column, value := range values {
+		if value == "" || pii.IsEncrypted(value) {
+			continue
+		}
+		encrypted, err := keys.Encrypt(value)
+		if err != nil {
+			return nil, err
+		}
+		columns[column] = encrypted
+	}
+	return columns, nil
+}
+
+// mobileHash returns the lookup hash of a stored mobile number, encrypted or not
+func mobileHash(keys *pii.KeyRing, stored string) (string, error) {
+	mobile := stored
+	if pii.IsEncrypted(stored) {
+		var err error
+		if mobile, err = keys.Decrypt(stored); err != nil {
+			return "", err
+		}
+	}
+	return keys.Hash(mobile), nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"hrms/internal/pii"
+)
+
+func newTestKeyRing(t *testing.T) *pii.KeyRing {
+	t.Helper()
+	keys, err := pii.NewKeyRing(pii.Config{
+		Keys:        map[string]string{"k1": base64.StdEncoding.EncodeToString([]byte(strings.Repeat("a", 32)))},
+		ActiveKeyID: "k1",
+		HashKey:     "hash-secret",
+	})
+	if err != nil {
+		t.Fatalf("NewKeyRing: %v", err)
+	}
+	return keys
+}
+
+func TestEncryptRow(t *testing.T) {
+	keys := newTestKeyRing(t)
+	encrypted, _ := keys.Encrypt("asha@example.org")
+
+	tests := []struct {
+		name        string
+		values      map[string]string
+		wantColumns []string
+	}{
+		{
+			name:        "plaintext columns are encrypted",
+			values:      map[string]string{"mobile_number": "9876543210", "pan_number": "ABCDE1234F"},
+			wantColumns: []string{"mobile_number", "pan_number"},
+		},
+		{
+			name:        "encrypted and empty columns are left alone",
+			values:      map[string]string{"mobile_number": "9876543210", "email": encrypted, "aadhaar_number": ""},
+			wantColumns: []string{"mobile_number"},
+		},
+		{
+			name:   "already encrypted row needs no update",
+			values: map[string]string{"email": encrypted},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, err := encryptRow(keys, tt.values)
+			if err != nil {
+				t.Fatalf("encryptRow: %v", err)
+			}
+			if len(columns) != len(tt.wantColumns) {
+				t.Fatalf("columns = %v, want %v", columns, tt.wantColumns)
+			}
+			for _, column := range tt.wantColumns {
+				value, _ := columns[column].(string)
+				decrypted, err := keys.Decrypt(value)
+				if err != nil || decrypted != tt.values[column] {
+					t.Fatalf("%s decrypts to %q, %v; want %q", column, decrypted, err, tt.values[column])
+				}
+			}
+		})
+	}
+}
+
+func TestMobileHash(t *testing.T) {
+	keys := newTestKeyRing(t)
+	encrypted, _ := keys.Encrypt("9876543210")
+	want := keys.Hash("9876543210")
+
+	tests := []struct {
+		name    string
+		stored  string
+		wantErr bool
+	}{
+		{name: "plaintext", stored: "9876543210"},
+		{name: "encrypted", stored: encrypted},
+		{name: "undecryptable", stored: "enc:k9:AAAA", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mobileHash(keys, tt.stored)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("mobileHash = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil || got != want {
+				t.Fatalf("mobileHash = %q, %v; want the hash of the plaintext", got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"hrms/internal/models"
+	"hrms/pkg/errors"
+)
+
+// QualificationRepository defines the interface for educational detail and
+// departmental test data access operations
+type QualificationRepository interface {
+	// CreateEducationalDetail creates a new educational detail record
+	CreateEducationalDetail(ctx context.Context, detail *models.EducationalDetail) error
+
+	// FindEducationalDetailsByEmployeeID returns all educational details of an employee
+	FindEducationalDetailsByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.EducationalDetail, error)
+
+	// DeleteEducationalDetailsByEmployeeID deletes all educational details of an employee
+	DeleteEducationalDetailsByEmployeeID(ctx context.Context, employeeID, tenantID string) error
+
+	// CreateDepartmentalTest creates a new departmental test record
+	CreateDepartmentalTest(ctx context.Context, test *models.DepartmentalTest) error
+
+	// FindDepartmentalTestsByEmployeeID returns all departmental tests of an employee
+	FindDepartmentalTestsByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.DepartmentalTest, error)
+
+	// DeleteDepartmentalTestsByEmployeeID deletes all departmental tests of an employee
+	DeleteDepartmentalTestsByEmployeeID(ctx context.Context, employeeID, tenantID string) error
+}
+
+type qualificationRepository struct {
+	db *gorm.DB
+}
+
+// NewQualificationRepository creates a new qualification repository
+func NewQualificationRepository(db *gorm.DB) QualificationRepository {
+	return &qualificationRepository{
+		db: db,
+	}
+}
+
+func (r *qualificationRepository) CreateEducationalDetail(ctx context.Context, detail *models.EducationalDetail) error {
+	now := time.Now().Unix()
+	detail.CreatedTime = now
+	detail.LastModifiedTime = &now
+
+	tx := conn(ctx, r.db).Model(&models.EducationalDetail{}).Create(detail)
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to create educational detail")
+	}
+	return nil
+}
+
+func (r *qualificationRepository) FindEducationalDetailsByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.EducationalDetail, error) {
+	var details []*models.EducationalDetail
+	tx := conn(ctx, r.db).Model(&models.EducationalDetail{}).
+		Where("employee_id = ? AND tenant_id = ?", employeeID, tenantID).
+		Order("year_of_passing ASC").
+		Find(&details)
+
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to find educational details by employee ID")
+	}
+	return details, nil
+}
+
+func (r *qualificationRepository) DeleteEducationalDetailsByEmployeeID(ctx context.Context, employeeID, tenantID string) error {
+	tx := conn(ctx, r.db).
+		Where("employee_id = ? AND tenant_id = ?", employeeID, tenantID).
+		Delete(&models.EducationalDetail{})
+
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to delete educational details")
+	}
+	return nil
+}
+
+func (r *qualificationRepository) CreateDepartmentalTest(ctx context.Context, test *models.DepartmentalTest) error {
+	now := time.Now().Unix()
+	test.CreatedTime = now
+	test.LastModifiedTime = &now
+
+	tx := conn(ctx, r.db).Model(&models.DepartmentalTest{}).Create(test)
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to create departmental test")
+	}
+	return nil
+}
+
+func (r *qualificationRepository) FindDepartmentalTestsByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.DepartmentalTest, error) {
+	var tests []*models.DepartmentalTest
+	tx := conn(ctx, r.db).Model(&models.DepartmentalTest{}).
+		Where("employee_id = ? AND tenant_id = ?", employeeID, tenantID).
+		Order("year_of_passing ASC").
+		Find(&tests)
+
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to find departmental tests by employee ID")
+	}
+	return tests, nil
+}
+
+func (r *qualificationRepository) DeleteDepartmentalTestsByEmployeeID(ctx context.Context, employeeID, tenantID string) error {
+	tx := conn(ctx, r.db).
+		Where("employee_id = ? AND tenant_id = ?", employeeID, tenantID).
+		Delete(&models.DepartmentalTest{})
+
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to delete departmental tests")
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"hrms/internal/models"
+	"hrms/pkg/errors"
+)
+
+// ServiceHistoryRepository defines the interface for service history data access operations
+type ServiceHistoryRepository interface {
+	// Create creates a new service history record
+	Create(ctx context.Context, record *models.ServiceHistory) error
+
+	// FindByEmployeeID returns all service history records of an employee ordered by service_from
+	FindByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.ServiceHistory, error)
+
+	// DeleteByEmployeeID deletes all service history records of an employee
+	DeleteByEmployeeID(ctx context.Context, employeeID, tenantID string) error
+}
+
+type serviceHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewServiceHistoryRepository creates a new service history repository
+func NewServiceHistoryRepository(db *gorm.DB) ServiceHistoryRepository {
+	return &serviceHistoryRepository{
+		db: db,
+	}
+}
+
+func (r *serviceHistoryRepository) Create(ctx context.Context, record *models.ServiceHistory) error {
+	now := time.Now().Unix()
+	record.CreatedTime = now
+	record.LastModifiedTime = &now
+
+	tx := conn(ctx, r.db).Model(&models.ServiceHistory{}).Create(record)
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to create service history")
+	}
+	return nil
+}
+
+func (r *serviceHistoryRepository) FindByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.ServiceHistory, error) {
+	var records []*models.ServiceHistory
+	tx := conn(ctx, r.db).Model(&models.ServiceHistory{}).
+		Where("employee_id = ? AND tenant_id = ?", employeeID, tenantID).
+		Order("service_from ASC NULLS LAST").
+		Find(&records)
+
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to find service history by employee ID")
+	}
+
+	return records, nil
+}
+
+func (r *serviceHistoryRepository) DeleteByEmployeeID(ctx context.Context, employeeID, tenantID string) error {
+	tx := conn(ctx, r.db).
+		Where("employee_id = ? AND tenant_id = ?", employeeID, tenantID).
+		Delete(&models.ServiceHistory{})
+
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "DATABASE_ERROR", "failed to delete service history")
+	}
+	return nil
+}
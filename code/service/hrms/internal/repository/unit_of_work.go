@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"hrms/internal/requestctx"
+)
+
+// txKey is the context key under which the active transaction is stored
+type txKey struct{}
+
+// UnitOfWork runs a function inside a single database transaction. Every repository
+// called with the context handed to fn joins that transaction, so the writes either
+// all commit or all roll back.
+type UnitOfWork interface {
+	// Do runs fn in a transaction. If ctx already carries a transaction, fn joins it
+	// instead of starting a new one.
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+// NewUnitOfWork creates a new unit of work backed by db
+func NewUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &unitOfWork{
+		db: db,
+	}
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn returns the transaction carried by ctx, or db when there is none
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// inTx runs fn in the transaction carried by ctx, or in a new one when there is none,
+// so that a write and its audit entry commit together
+func inTx(ctx context.Context, db *gorm.DB, fn func(ctx context.Context) error) error {
+	return (&unitOfWork{db: db}).Do(ctx, fn)
+}
+
+// modified adds the actor of ctx and the current time as the last modification to a
+// column update map
+func modified(ctx context.Context, columns map[string]interface{}) map[string]interface{} {
+	columns["last_modified_by"] = requestctx.ActorID(ctx)
+	columns["last_modified_time"] = time.Now().Unix()
+	return columns
+}
@@ -0,0 +1,43 @@
+package requestctx
+
+import "context"
+
+// System is the actor recorded for writes no caller asked for, such as scheduled
+// status changes
+const System = "system"
+
+// Actor identifies who made a request: the authenticated user, or the calling client
+// when there is no user
+type Actor struct {
+	UserID   string
+	ClientID string
+}
+
+// ID returns the user ID, else the client ID, else System
+func (a Actor) ID() string {
+	if a.UserID != "" {
+		return a.UserID
+	}
+	if a.ClientID != "" {
+		return a.ClientID
+	}
+	return System
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying the actor
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor carried by ctx, or the zero Actor when there is none
+func ActorFrom(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+	return actor
+}
+
+// ActorID returns the ID of the actor carried by ctx, or System when there is none
+func ActorID(ctx context.Context) string {
+	return ActorFrom(ctx).ID()
+}
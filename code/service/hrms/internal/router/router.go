@@ -6,14 +6,25 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 
+	"hrms/internal/auth"
 	"hrms/internal/config"
 	"hrms/internal/handler"
 	"hrms/internal/middleware"
 )
 
-// SetupRouter initializes and configures the HTTP router
+// Roles required by the routes that change data. Reads are open to every authenticated caller.
+var (
+	// writeRoles may create and change employees and jurisdictions
+	writeRoles = []string{"HRMS_ADMIN", "SUPERUSER"}
+	// deleteRoles may hard-delete employees
+	deleteRoles = []string{"HRMS_ADMIN"}
+)
+
+// SetupRouter initializes and configures the HTTP router. Requests are authenticated with
+// the verifier; a nil verifier disables authentication and role checks.
 func SetupRouter(
 	cfg *config.Config,
+	verifier *auth.Verifier,
 	employeeHandler *handler.EmployeeHandler,
 	jurisdictionHandler *handler.JurisdictionHandler,
 	logger *logrus.Logger,
@@ -22,10 +33,23 @@ func SetupRouter(
 	r := gin.New()
 
 	// Middleware
-	r.Use(gin.Recovery())
 	r.Use(middleware.Logger())
+	r.Use(middleware.ErrorHandler(logger))
+	r.Use(middleware.Recovery(logger))
 	r.Use(middleware.Headers(logger))
 
+	requireRoles := middleware.RequireRoles
+	if verifier != nil {
+		r.Use(middleware.Auth(verifier, logger))
+	} else {
+		logger.Warn("Authentication is disabled; every request is trusted")
+		requireRoles = func(...string) gin.HandlerFunc {
+			return func(c *gin.Context) { c.Next() }
+		}
+	}
+	r.Use(middleware.Actor())
+	canWrite := requireRoles(writeRoles...)
+
 	// Health check endpoint
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -37,33 +61,46 @@ func SetupRouter(
 	v3 := r.Group(cfg.Server.ContextPath + "/employees/v3")
 	{
 		// Employee endpoints
-		v3.POST("", employeeHandler.CreateEmployees)
+		v3.POST("", canWrite, employeeHandler.CreateEmployees)
 		v3.GET("", employeeHandler.SearchEmployees)
+		v3.GET("/_count", employeeHandler.CountEmployees)
+		v3.GET("/_resolve", employeeHandler.ResolveEmployees)
 
 		// Employee by ID endpoints
 		employeeID := v3.Group("/:id")
 		{
 			employeeID.GET("", employeeHandler.GetEmployeeByUUID)
-			employeeID.PUT("", employeeHandler.UpdateEmployee)
-			employeeID.DELETE("", employeeHandler.HardDeleteEmployee)
-			employeeID.PATCH("", employeeHandler.PatchEmployee)
+			employeeID.PUT("", canWrite, employeeHandler.UpdateEmployee)
+			employeeID.DELETE("", requireRoles(deleteRoles...), employeeHandler.HardDeleteEmployee)
+			employeeID.PATCH("", canWrite, employeeHandler.PatchEmployee)
 
 			// Employee status management
-			employeeID.POST("deactivate", employeeHandler.DeactivateEmployee)
-			employeeID.POST("reactivate", employeeHandler.ReactivateEmployee)
+			employeeID.POST("deactivate", canWrite, employeeHandler.DeactivateEmployee)
+			employeeID.POST("reactivate", canWrite, employeeHandler.ReactivateEmployee)
+			employeeID.GET("status-history", employeeHandler.GetStatusHistory)
+			employeeID.GET("audit", employeeHandler.GetAuditLog)
+			employeeID.GET("pending-actions", employeeHandler.ListPendingActions)
+			employeeID.DELETE("pending-actions/:actionId", canWrite, employeeHandler.CancelPendingAction)
+
+			// Jurisdictions of the employee
+			employeeID.GET("jurisdictions", jurisdictionHandler.GetEmployeeJurisdictions)
+			employeeID.POST("jurisdictions", canWrite, jurisdictionHandler.CreateEmployeeJurisdiction)
+			employeeID.PUT("jurisdictions", canWrite, jurisdictionHandler.ReplaceEmployeeJurisdictions)
 		}
 
 		// Jurisdiction endpoints
 		jurisdiction := v3.Group("/jurisdictions")
 		{
-			jurisdiction.POST("", jurisdictionHandler.CreateJurisdiction)
+			jurisdiction.POST("", canWrite, jurisdictionHandler.CreateJurisdiction)
 			jurisdiction.GET("", jurisdictionHandler.SearchJurisdictions)
 
 			// Jurisdiction by UUID endpoints
 			jurisdictionUUID := jurisdiction.Group("/:uuid")
 			{
 				jurisdictionUUID.GET("", jurisdictionHandler.GetJurisdictionByUUID)
-				jurisdictionUUID.PUT("", jurisdictionHandler.ReplaceJurisdiction)
+				jurisdictionUUID.PUT("", canWrite, jurisdictionHandler.ReplaceJurisdiction)
+				jurisdictionUUID.PATCH("", canWrite, jurisdictionHandler.UpdateJurisdiction)
+				jurisdictionUUID.DELETE("", canWrite, jurisdictionHandler.DeleteJurisdiction)
 			}
 		}
 	}
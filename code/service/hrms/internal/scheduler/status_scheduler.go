@@ -0,0 +1,62 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"hrms/internal/service"
+)
+
+// StatusScheduler periodically applies future-dated deactivations and reactivations.
+// Pending actions are stored in the database, so nothing is lost across restarts; any
+// action that fell due while the service was down is applied on the first run.
+type StatusScheduler struct {
+	employeeSvc service.EmployeeService
+	interval    time.Duration
+	logger      *logrus.Logger
+}
+
+// NewStatusScheduler creates a new status scheduler
+func NewStatusScheduler(employeeSvc service.EmployeeService, interval time.Duration, logger *logrus.Logger) *StatusScheduler {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &StatusScheduler{
+		employeeSvc: employeeSvc,
+		interval:    interval,
+		logger:      logger,
+	}
+}
+
+// Start runs the scheduler in the background until ctx is cancelled
+func (s *StatusScheduler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		s.runOnce(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				s.logger.Info("Status scheduler stopped")
+				return
+			case <-ticker.C:
+				s.runOnce(ctx)
+			}
+		}
+	}()
+}
+
+// runOnce applies all status changes that are due
+func (s *StatusScheduler) runOnce(ctx context.Context) {
+	applied, err := s.employeeSvc.ApplyDueStatusActions(ctx, time.Now().UTC())
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to apply scheduled status changes")
+		return
+	}
+	if applied > 0 {
+		s.logger.Infof("Applied %d scheduled status changes", applied)
+	}
+}
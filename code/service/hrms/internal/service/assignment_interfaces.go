@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"hrms/internal/models"
+)
+
+// AssignmentService defines the interface for employee assignment operations
+type AssignmentService interface {
+	// ValidateAssignments checks a set of assignments for missing fields, overlapping
+	// periods and more than one active assignment
+	ValidateAssignments(assignments []*models.Assignment) error
+
+	// CreateAssignments creates the given assignments for an employee
+	CreateAssignments(ctx context.Context, employeeID string, assignments []*models.Assignment, tenantID string) ([]*models.Assignment, error)
+
+	// ReplaceAssignments updates assignments that carry an ID and creates the rest.
+	// Existing assignments missing from the request are retained, as assignments are never deleted.
+	ReplaceAssignments(ctx context.Context, employeeID string, assignments []*models.Assignment, tenantID string) ([]*models.Assignment, error)
+
+	// GetAssignmentsByEmployeeID retrieves all assignments of an employee
+	GetAssignmentsByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.Assignment, error)
+}
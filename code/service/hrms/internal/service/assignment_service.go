@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"hrms/internal/models"
+	"hrms/internal/repository"
+	"hrms/pkg/errors"
+)
+
+type assignmentService struct {
+	repo repository.AssignmentRepository
+}
+
+// NewAssignmentService creates a new assignment service
+func NewAssignmentService(repo repository.AssignmentRepository) AssignmentService {
+	return &assignmentService{
+		repo: repo,
+	}
+}
+
+// ValidateAssignments enforces the assignment rules: every assignment needs a department,
+// designation and fromDate, periods must not overlap and at most one may be current
+func (s *assignmentService) ValidateAssignments(assignments []*models.Assignment) error {
+	active := 0
+	for _, a := range assignments {
+		if a.Department == "" || a.Designation == "" {
+			return errors.New("VALIDATION_ERROR", "department and designation are required for every assignment")
+		}
+		if a.FromDate == nil {
+			return errors.New("VALIDATION_ERROR", "fromDate is required for every assignment")
+		}
+		if a.ToDate != nil && !a.ToDate.After(*a.FromDate) {
+			return errors.New("VALIDATION_ERROR", "assignment toDate must be after fromDate")
+		}
+		if a.IsCurrentAssignment {
+			active++
+		}
+	}
+
+	if active > 1 {
+		return errors.New("MULTIPLE_ACTIVE_ASSIGNMENTS", "employee cannot have more than one active assignment")
+	}
+
+	// Once sorted by start date, any overlap shows up between neighbours
+	sorted := make([]*models.Assignment, len(assignments))
+	copy(sorted, assignments)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].FromDate.Before(*sorted[j].FromDate)
+	})
+
+	for i := 1; i < len(sorted); i++ {
+		prev, cur := sorted[i-1], sorted[i]
+		if prev.ToDate == nil || prev.ToDate.After(*cur.FromDate) {
+			return errors.New("ASSIGNMENT_OVERLAP", "assignment periods overlap").
+				WithDescription(fmt.Sprintf("assignment starting %s overlaps assignment starting %s",
+					cur.FromDate.Format("2006-01-02"), prev.FromDate.Format("2006-01-02")))
+		}
+	}
+
+	return nil
+}
+
+// CreateAssignments creates the given assignments for an employee
+func (s *assignmentService) CreateAssignments(ctx context.Context, employeeID string, assignments []*models.Assignment, tenantID string) ([]*models.Assignment, error) {
+	existing, err := s.repo.FindByEmployeeID(ctx, employeeID, tenantID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch existing assignments")
+		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to fetch assignments").WithOperation("CreateAssignments")
+	}
+
+	toCreate := make([]*models.Assignment, 0, len(assignments))
+	for _, a := range assignments {
+		toCreate = append(toCreate, newAssignment(a, employeeID, tenantID))
+	}
+
+	if err := s.ValidateAssignments(append(existing, toCreate...)); err != nil {
+		return nil, err
+	}
+
+	for _, a := range toCreate {
+		if err := s.repo.Create(ctx, a); err != nil {
+			logrus.WithError(err).Error("Failed to create assignment")
+			return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to create assignment").WithOperation("CreateAssignments")
+		}
+	}
+
+	return s.GetAssignmentsByEmployeeID(ctx, employeeID, tenantID)
+}
+
+// ReplaceAssignments updates assignments that carry an ID and creates the rest
+func (s *assignmentService) ReplaceAssignments(ctx context.Context, employeeID string, assignments []*models.Assignment, tenantID string) ([]*models.Assignment, error) {
+	existing, err := s.repo.FindByEmployeeID(ctx, employeeID, tenantID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch existing assignments")
+		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to fetch assignments").WithOperation("ReplaceAssignments")
+	}
+
+	byID := make(map[string]*models.Assignment, len(existing))
+	for _, a := range existing {
+		byID[a.ID] = a
+	}
+
+	var toUpdate, toCreate []*models.Assignment
+	for _, a := range assignments {
+		if a.ID == "" {
+			toCreate = append(toCreate, newAssignment(a, employeeID, tenantID))
+			continue
+		}
+
+		current, ok := byID[a.ID]
+		if !ok {
+			return nil, errors.New("NOT_FOUND", "assignment not found").
+				WithDescription(fmt.Sprintf("assignment %s does not belong to employee %s", a.ID, employeeID)).
+				WithOperation("ReplaceAssignments")
+		}
+		current.Department = a.Department
+		current.Designation = a.Designation
+		current.ReportingTo = a.ReportingTo
+		current.IsHOD = a.IsHOD
+		current.IsCurrentAssignment = a.IsCurrentAssignment
+		current.FromDate = a.FromDate
+		current.ToDate = a.ToDate
+		toUpdate = append(toUpdate, current)
+	}
+
+	if err := s.ValidateAssignments(append(existing, toCreate...)); err != nil {
+		return nil, err
+	}
+
+	// Clear current flags before setting new ones so the single-current index is never violated
+	sort.SliceStable(toUpdate, func(i, j int) bool {
+		return !toUpdate[i].IsCurrentAssignment && toUpdate[j].IsCurrentAssignment
+	})
+
+	for _, a := range toUpdate {
+		if err := s.repo.Update(ctx, a); err != nil {
+			logrus.WithError(err).Error("Failed to update assignment")
+			return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to update assignment").WithOperation("ReplaceAssignments")
+		}
+	}
+
+	for _, a := range toCreate {
+		if err := s.repo.Create(ctx, a); err != nil {
+			logrus.WithError(err).Error("Failed to create assignment")
+			return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to create assignment").WithOperation("ReplaceAssignments")
+		}
+	}
+
+	return s.GetAssignmentsByEmployeeID(ctx, employeeID, tenantID)
+}
+
+// GetAssignmentsByEmployeeID retrieves all assignments of an employee
+func (s *assignmentService) GetAssignmentsByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.Assignment, error) {
+	assignments, err := s.repo.FindByEmployeeID(ctx, employeeID, tenantID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get assignments by employee ID")
+		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to get assignments by employee ID").WithOperation("GetAssignmentsByEmployeeID")
+	}
+	return assignments, nil
+}
+
+// newAssignment builds a fresh assignment record from request data
+func newAssignment(a *models.Assignment, employeeID, tenantID string) *models.Assignment {
+	return &models.Assignment{
+		ID:                  uuid.New().String(),
+		EmployeeID:          employeeID,
+		Department:          a.Department,
+		Designation:         a.Designation,
+		ReportingTo:         a.ReportingTo,
+		IsHOD:               a.IsHOD,
+		IsCurrentAssignment: a.IsCurrentAssignment,
+		FromDate:            a.FromDate,
+		ToDate:              a.ToDate,
+		TenantID:            tenantID,
+		CreatedBy:           "system",
+	}
+}
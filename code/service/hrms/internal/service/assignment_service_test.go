@@ -0,0 +1,127 @@
+package service
+
+import (
+	"testing"
+
+	"hrms/internal/models"
+	"hrms/pkg/errors"
+)
+
+func TestValidateAssignments(t *testing.T) {
+	assignment := func(from, to string, current bool) *models.Assignment {
+		a := &models.Assignment{Department: "DEPT_1", Designation: "DESIG_1", IsCurrentAssignment: current}
+		if from != "" {
+			a.FromDate = date(from)
+		}
+		if to != "" {
+			a.ToDate = date(to)
+		}
+		return a
+	}
+
+	tests := []struct {
+		name        string
+		assignments []*models.Assignment
+		wantCode    string // "" means valid
+	}{
+		{
+			name:        "no assignments",
+			assignments: nil,
+		},
+		{
+			name:        "single open-ended current assignment",
+			assignments: []*models.Assignment{assignment("2020-01-01", "", true)},
+		},
+		{
+			name: "consecutive periods",
+			assignments: []*models.Assignment{
+				assignment("2021-01-01", "", true),
+				assignment("2020-01-01", "2021-01-01", false),
+			},
+		},
+		{
+			name: "gap between periods",
+			assignments: []*models.Assignment{
+				assignment("2019-01-01", "2019-06-30", false),
+				assignment("2020-01-01", "", true),
+			},
+		},
+		{
+			name: "overlapping closed periods",
+			assignments: []*models.Assignment{
+				assignment("2020-01-01", "2020-12-31", false),
+				assignment("2020-06-01", "2021-06-01", false),
+			},
+			wantCode: "ASSIGNMENT_OVERLAP",
+		},
+		{
+			name: "later period starts inside an open-ended one",
+			assignments: []*models.Assignment{
+				assignment("2020-01-01", "", false),
+				assignment("2022-01-01", "2022-06-01", false),
+			},
+			wantCode: "ASSIGNMENT_OVERLAP",
+		},
+		{
+			name: "period contained in another, given out of order",
+			assignments: []*models.Assignment{
+				assignment("2020-03-01", "2020-04-01", false),
+				assignment("2020-01-01", "2020-12-31", false),
+			},
+			wantCode: "ASSIGNMENT_OVERLAP",
+		},
+		{
+			name: "same start date",
+			assignments: []*models.Assignment{
+				assignment("2020-01-01", "2020-06-01", false),
+				assignment("2020-01-01", "2020-03-01", false),
+			},
+			wantCode: "ASSIGNMENT_OVERLAP",
+		},
+		{
+			name: "two current assignments",
+			assignments: []*models.Assignment{
+				assignment("2019-01-01", "2020-01-01", true),
+				assignment("2020-01-01", "", true),
+			},
+			wantCode: "MULTIPLE_ACTIVE_ASSIGNMENTS",
+		},
+		{
+			name:        "missing fromDate",
+			assignments: []*models.Assignment{assignment("", "", true)},
+			wantCode:    errors.ErrValidationFailed.Code,
+		},
+		{
+			name:        "toDate before fromDate",
+			assignments: []*models.Assignment{assignment("2020-06-01", "2020-01-01", false)},
+			wantCode:    errors.ErrValidationFailed.Code,
+		},
+		{
+			name:        "toDate equal to fromDate",
+			assignments: []*models.Assignment{assignment("2020-06-01", "2020-06-01", false)},
+			wantCode:    errors.ErrValidationFailed.Code,
+		},
+		{
+			name:        "missing department",
+			assignments: []*models.Assignment{{Designation: "DESIG_1", FromDate: date("2020-01-01")}},
+			wantCode:    errors.ErrValidationFailed.Code,
+		},
+	}
+
+	s := &assignmentService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.ValidateAssignments(tt.assignments)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			e, ok := err.(*errors.Error)
+			if !ok || e.Code != tt.wantCode {
+				t.Fatalf("error = %v, want code %s", err, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+
+	"hrms/internal/models"
+	"hrms/pkg/errors"
+)
+
+// GetAuditLog retrieves a page of the changes made to an employee and its jurisdictions.
+// The log outlives the employee, so entries of a deleted employee are still returned.
+func (s *employeeService) GetAuditLog(ctx context.Context, uuid string, criteria *models.AuditSearchCriteria) (*models.AuditLogResponse, error) {
+	if err := s.validator.ValidateAuditSearch(criteria); err != nil {
+		return nil, err
+	}
+	criteria.EmployeeID = uuid
+
+	entries, total, err := s.auditRepo.Search(ctx, criteria)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get audit log")
+		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to get audit log").WithOperation("GetAuditLog")
+	}
+
+	// An employee that never existed has no entries at all
+	if total == 0 && criteria.Field == "" && criteria.Actor == "" {
+		if _, err := s.findEmployee(ctx, uuid, criteria.TenantID, "GetAuditLog"); err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.AuditLogResponse{
+		Entries:    entries,
+		TotalCount: total,
+		Limit:      criteria.Limit,
+		Offset:     criteria.Offset,
+	}, nil
+}
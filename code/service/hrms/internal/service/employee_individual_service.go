@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"hrms/internal/clients/individual"
+	"hrms/internal/models"
+	"hrms/pkg/errors"
+)
+
+// resolveIndividual returns the Individual ID to link a new employee to. A given
+// individualId must exist; otherwise an Individual registered with the employee's
+// mobile number is reused when its name and date of birth match the request, or a new
+// one is created from the request.
+// Without an Individual client the requested ID is kept as it is.
+func (s *employeeService) resolveIndividual(ctx context.Context, r *models.CreateEmployeeRequest, tenantID string) (string, error) {
+	if s.individualClient == nil {
+		return r.IndividualID, nil
+	}
+
+	if r.IndividualID != "" {
+		if _, err := s.findIndividual(ctx, r.IndividualID, tenantID); err != nil {
+			return "", err
+		}
+		return r.IndividualID, nil
+	}
+
+	if r.MobileNumber != "" {
+		existing, err := s.individualClient.SearchByMobileNumber(ctx, tenantID, r.MobileNumber)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to search individuals by mobile number")
+			return "", errors.Wrap(err, "INDIVIDUAL_SERVICE_ERROR", "failed to search individuals").WithOperation("CreateEmployees")
+		}
+		// A shared or recycled mobile number is not proof of identity, so only a record
+		// of the same person is reused
+		for _, candidate := range existing {
+			if samePerson(candidate, r) {
+				return candidate.ID, nil
+			}
+		}
+	}
+
+	if r.Name == nil || r.Name.GivenName == "" {
+		return "", errors.NewFieldError("name", "name.givenName is required when individualId is not provided")
+	}
+
+	// The Individual is created outside the employee transaction. If the employee
+	// write fails, a retry with the same name and date of birth finds it again through
+	// the mobile number.
+	created, err := s.individualClient.Create(ctx, tenantID, &individual.Individual{
+		TenantID: tenantID,
+		Name: individual.Name{
+			GivenName:  r.Name.GivenName,
+			FamilyName: r.Name.FamilyName,
+			OtherNames: r.Name.OtherNames,
+		},
+		MobileNumber: r.MobileNumber,
+		Email:        r.Email,
+		Gender:       r.Gender,
+		DateOfBirth:  r.DateOfBirth,
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create individual")
+		return "", errors.Wrap(err, "INDIVIDUAL_SERVICE_ERROR", "failed to create individual").WithOperation("CreateEmployees")
+	}
+
+	return created.ID, nil
+}
+
+// samePerson reports whether an Individual is the person described by a create request:
+// given and family name agree ignoring case and surrounding spaces, and so does the date
+// of birth, which must be present on both sides
+func samePerson(candidate *individual.Individual, r *models.CreateEmployeeRequest) bool {
+	if r.Name == nil || r.DateOfBirth == nil || candidate.DateOfBirth == nil {
+		return false
+	}
+	sameName := func(a, b string) bool {
+		return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+	}
+	if !sameName(candidate.Name.GivenName, r.Name.GivenName) || !sameName(candidate.Name.FamilyName, r.Name.FamilyName) {
+		return false
+	}
+	return candidate.DateOfBirth.UTC().Format("2006-01-02") == r.DateOfBirth.UTC().Format("2006-01-02")
+}
+
+// validateIndividualID checks that an individualId refers to an existing Individual
+func (s *employeeService) validateIndividualID(ctx context.Context, individualID, tenantID string) error {
+	if s.individualClient == nil || individualID == "" {
+		return nil
+	}
+	_, err := s.findIndividual(ctx, individualID, tenantID)
+	return err
+}
+
+// findIndividual looks up an Individual and maps a missing record to a field error
+func (s *employeeService) findIndividual(ctx context.Context, individualID, tenantID string) (*individual.Individual, error) {
+	found, err := s.individualClient.GetByID(ctx, tenantID, individualID)
+	if err != nil {
+		if err == individual.ErrNotFound {
+			return nil, errors.NewFieldError("individualId", fmt.Sprintf("individual %s does not exist", individualID))
+		}
+		logrus.WithError(err).Error("Failed to look up individual")
+		return nil, errors.Wrap(err, "INDIVIDUAL_SERVICE_ERROR", "failed to look up individual")
+	}
+	return found, nil
+}
+
+// individualsOf fetches the name and contact details of the employees' Individuals in
+// one request, keyed by Individual ID. Lookup failures are logged and the employees are
+// returned without them.
+func (s *employeeService) individualsOf(ctx context.Context, tenantID string, emps ...*models.Employee) map[string]*models.IndividualDetails {
+	if s.individualClient == nil {
+		return nil
+	}
+
+	ids := make([]string, 0, len(emps))
+	seen := make(map[string]bool, len(emps))
+	for _, emp := range emps {
+		if emp.IndividualID != "" && !seen[emp.IndividualID] {
+			seen[emp.IndividualID] = true
+			ids = append(ids, emp.IndividualID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	found, err := s.individualClient.GetByIDs(ctx, tenantID, ids)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch individuals for employees")
+		return nil
+	}
+
+	details := make(map[string]*models.IndividualDetails, len(found))
+	for _, ind := range found {
+		details[ind.ID] = &models.IndividualDetails{
+			ID: ind.ID,
+			Name: models.PersonName{
+				GivenName:  ind.Name.GivenName,
+				FamilyName: ind.Name.FamilyName,
+				OtherNames: ind.Name.OtherNames,
+			},
+			MobileNumber: ind.MobileNumber,
+			Email:        ind.Email,
+		}
+	}
+	return details
+}
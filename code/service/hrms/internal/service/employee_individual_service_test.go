@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hrms/internal/clients/individual"
+	"hrms/internal/clients/individual/individualtest"
+	"hrms/internal/models"
+	"hrms/pkg/errors"
+)
+
+const (
+	testTenant         = "pg.citya"
+	testIndividualPath = "/individual/v1"
+)
+
+// countingClient records how many lookups reach the Individual service
+type countingClient struct {
+	individual.Client
+	lookups int
+}
+
+func (c *countingClient) GetByID(ctx context.Context, tenantID, id string) (*individual.Individual, error) {
+	c.lookups++
+	return c.Client.GetByID(ctx, tenantID, id)
+}
+
+func (c *countingClient) GetByIDs(ctx context.Context, tenantID string, ids []string) ([]*individual.Individual, error) {
+	c.lookups++
+	return c.Client.GetByIDs(ctx, tenantID, ids)
+}
+
+func newIndividualStub(t *testing.T) (*individualtest.StubServer, *countingClient) {
+	t.Helper()
+	stub := individualtest.NewStubServer(testIndividualPath)
+	t.Cleanup(stub.Close)
+	client := individual.NewClient(individual.Config{Host: stub.URL, Path: testIndividualPath, Timeout: 5 * time.Second})
+	return stub, &countingClient{Client: client}
+}
+
+func date(s string) *time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return &t
+}
+
+// fieldOf returns the field of a validation error, or "" for any other error
+func fieldOf(err error) string {
+	e, ok := err.(*errors.Error)
+	if !ok || e.Code != errors.ErrValidationFailed.Code {
+		return ""
+	}
+	fields, _ := e.Params.(map[string]string)
+	return fields["field"]
+}
+
+func TestResolveIndividual(t *testing.T) {
+	existing := &individual.Individual{
+		ID:           "ind-1",
+		TenantID:     testTenant,
+		Name:         individual.Name{GivenName: "Asha", FamilyName: "Rao"},
+		MobileNumber: "9876543210",
+		DateOfBirth:  date("1990-04-01"),
+	}
+
+	tests := []struct {
+		name      string
+		request   models.CreateEmployeeRequest
+		wantID    string // expected ID; "new" means a newly created Individual
+		wantField string // expected validation error field
+	}{
+		{
+			name:    "given individualId that exists is kept",
+			request: models.CreateEmployeeRequest{IndividualID: "ind-1"},
+			wantID:  "ind-1",
+		},
+		{
+			name:      "given individualId must exist",
+			request:   models.CreateEmployeeRequest{IndividualID: "ind-missing"},
+			wantField: "individualId",
+		},
+		{
+			name: "mobile match with the same name and birth date is linked",
+			request: models.CreateEmployeeRequest{
+				MobileNumber: "9876543210",
+				Name:         &models.PersonName{GivenName: " asha ", FamilyName: "RAO"},
+				DateOfBirth:  date("1990-04-01"),
+			},
+			wantID: "ind-1",
+		},
+		{
+			name: "mobile match with a different name creates a new Individual",
+			request: models.CreateEmployeeRequest{
+				MobileNumber: "9876543210",
+				Name:         &models.PersonName{GivenName: "Ravi", FamilyName: "Rao"},
+				DateOfBirth:  date("1990-04-01"),
+			},
+			wantID: "new",
+		},
+		{
+			name: "mobile match with a different birth date creates a new Individual",
+			request: models.CreateEmployeeRequest{
+				MobileNumber: "9876543210",
+				Name:         &models.PersonName{GivenName: "Asha", FamilyName: "Rao"},
+				DateOfBirth:  date("1991-04-01"),
+			},
+			wantID: "new",
+		},
+		{
+			name: "mobile match without a birth date creates a new Individual",
+			request: models.CreateEmployeeRequest{
+				MobileNumber: "9876543210",
+				Name:         &models.PersonName{GivenName: "Asha", FamilyName: "Rao"},
+			},
+			wantID: "new",
+		},
+		{
+			name:    "unknown mobile number creates a new Individual",
+			request: models.CreateEmployeeRequest{MobileNumber: "9000000000", Name: &models.PersonName{GivenName: "Asha"}},
+			wantID:  "new",
+		},
+		{
+			name:      "creating an Individual requires a given name",
+			request:   models.CreateEmployeeRequest{MobileNumber: "9000000000"},
+			wantField: "name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub, client := newIndividualStub(t)
+			stored := *existing
+			stub.Add(&stored)
+			s := &employeeService{individualClient: client}
+
+			id, err := s.resolveIndividual(context.Background(), &tt.request, testTenant)
+
+			if tt.wantField != "" {
+				if got := fieldOf(err); got != tt.wantField {
+					t.Fatalf("error = %v, want validation error on %q", err, tt.wantField)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantID != "new" {
+				if id != tt.wantID {
+					t.Fatalf("id = %q, want %q", id, tt.wantID)
+				}
+				return
+			}
+
+			if id == "" || id == existing.ID {
+				t.Fatalf("id = %q, want a newly created Individual", id)
+			}
+			created, err := client.GetByID(context.Background(), testTenant, id)
+			if err != nil {
+				t.Fatalf("created individual not found: %v", err)
+			}
+			if created.Name.GivenName != tt.request.Name.GivenName || created.MobileNumber != tt.request.MobileNumber {
+				t.Fatalf("created individual = %+v, want it built from the request", created)
+			}
+		})
+	}
+}
+
+func TestResolveIndividualWithoutClient(t *testing.T) {
+	s := &employeeService{}
+	id, err := s.resolveIndividual(context.Background(), &models.CreateEmployeeRequest{IndividualID: "ind-9"}, testTenant)
+	if err != nil || id != "ind-9" {
+		t.Fatalf("resolveIndividual = %q, %v; want the requested ID unchecked", id, err)
+	}
+}
+
+func TestValidateIndividualID(t *testing.T) {
+	tests := []struct {
+		name         string
+		individualID string
+		tenantID     string
+		wantField    string
+	}{
+		{name: "existing individual", individualID: "ind-1", tenantID: testTenant},
+		{name: "empty id is not checked", individualID: "", tenantID: testTenant},
+		{name: "unknown individual", individualID: "ind-missing", tenantID: testTenant, wantField: "individualId"},
+		{name: "individual of another tenant", individualID: "ind-1", tenantID: "pg.cityb", wantField: "individualId"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub, client := newIndividualStub(t)
+			stub.Add(&individual.Individual{ID: "ind-1", TenantID: testTenant, Name: individual.Name{GivenName: "Asha"}})
+			s := &employeeService{individualClient: client}
+
+			err := s.validateIndividualID(context.Background(), tt.individualID, tt.tenantID)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if got := fieldOf(err); got != tt.wantField {
+				t.Fatalf("error = %v, want validation error on %q", err, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestIndividualsOf(t *testing.T) {
+	tests := []struct {
+		name        string
+		employees   []*models.Employee
+		wantIDs     []string
+		wantLookups int
+	}{
+		{
+			name:        "no linked individuals makes no request",
+			employees:   []*models.Employee{{IndividualID: ""}, {IndividualID: ""}},
+			wantLookups: 0,
+		},
+		{
+			name: "a page is fetched in one request",
+			employees: []*models.Employee{
+				{IndividualID: "ind-1"}, {IndividualID: "ind-2"}, {IndividualID: "ind-1"}, {IndividualID: ""},
+			},
+			wantIDs:     []string{"ind-1", "ind-2"},
+			wantLookups: 1,
+		},
+		{
+			name:        "missing individuals are left out",
+			employees:   []*models.Employee{{IndividualID: "ind-1"}, {IndividualID: "ind-missing"}},
+			wantIDs:     []string{"ind-1"},
+			wantLookups: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub, client := newIndividualStub(t)
+			stub.Add(&individual.Individual{ID: "ind-1", TenantID: testTenant, Name: individual.Name{GivenName: "Asha"}, MobileNumber: "9876543210"})
+			stub.Add(&individual.Individual{ID: "ind-2", TenantID: testTenant, Name: individual.Name{GivenName: "Ravi"}})
+			s := &employeeService{individualClient: client}
+
+			got := s.individualsOf(context.Background(), testTenant, tt.employees...)
+
+			if client.lookups != tt.wantLookups {
+				t.Fatalf("lookups = %d, want %d", client.lookups, tt.wantLookups)
+			}
+			if len(got) != len(tt.wantIDs) {
+				t.Fatalf("got %d individuals, want %d", len(got), len(tt.wantIDs))
+			}
+			for _, id := range tt.wantIDs {
+				if got[id] == nil || got[id].ID != id {
+					t.Fatalf("individual %s missing from %v", id, got)
+				}
+			}
+		})
+	}
+}
@@ -3,20 +3,36 @@ package service
 
 import (
 	"context"
+	"time"
+
 	"hrms/internal/models"
 )
 
 // EmployeeService defines the interface for employee operations
 type EmployeeService interface {
-	// CreateEmployees creates one or more employees
+	// CreateEmployees creates one or more employees in a single transaction
 	CreateEmployees(ctx context.Context, req []*models.CreateEmployeeRequest, tenantID string) ([]*models.EmployeeResponse, error)
 
+	// CreateEmployeesPartial creates each employee independently and reports a result per item
+	CreateEmployeesPartial(ctx context.Context, req []*models.CreateEmployeeRequest, tenantID string) []*models.EmployeeBulkResult
+
 	// SearchEmployees searches for employees based on criteria
-	SearchEmployees(ctx context.Context, criteria *models.EmployeeSearchCriteria) ([]*models.EmployeeResponse, error)
+	SearchEmployees(ctx context.Context, criteria *models.EmployeeSearchCriteria) (*models.EmployeeSearchResponse, error)
+
+	// CountEmployees returns employee totals matching the criteria, grouped by active state,
+	// status, employee type and department
+	CountEmployees(ctx context.Context, criteria *models.EmployeeSearchCriteria) (*models.EmployeeCountResponse, error)
+
+	// ResolveEmployees finds the active employees responsible for a boundary, walking up its
+	// hierarchy, ranked from the most specific jurisdiction
+	ResolveEmployees(ctx context.Context, criteria *models.ResolveCriteria) (*models.ResolveResponse, error)
 
 	// GetEmployeeByUUID retrieves an employee by UUID
 	GetEmployeeByUUID(ctx context.Context, uuid, tenantID string) (*models.EmployeeResponse, error)
 
+	// GetEmployeeAsOf retrieves an employee and its jurisdictions as they were at the given time
+	GetEmployeeAsOf(ctx context.Context, uuid, tenantID string, asOf time.Time) (*models.EmployeeResponse, error)
+
 	// UpdateEmployee updates an employee by UUID
 	UpdateEmployee(ctx context.Context, uuid string, req *models.CreateEmployeeRequest, tenantID string) (*models.EmployeeResponse, error)
 
@@ -31,4 +47,19 @@ type EmployeeService interface {
 
 	// ReactivateEmployee reactivates an inactive employee
 	ReactivateEmployee(ctx context.Context, uuid string, req *models.ReactivationDetails, tenantID string) (*models.EmployeeResponse, error)
+
+	// GetStatusHistory retrieves the deactivation and reactivation history of an employee
+	GetStatusHistory(ctx context.Context, uuid, tenantID string) ([]*models.StatusHistory, error)
+
+	// GetAuditLog retrieves a page of the changes made to an employee and its jurisdictions
+	GetAuditLog(ctx context.Context, uuid string, criteria *models.AuditSearchCriteria) (*models.AuditLogResponse, error)
+
+	// ListPendingActions lists the scheduled status changes of an employee, optionally filtered by status
+	ListPendingActions(ctx context.Context, uuid, status, tenantID string) ([]*models.PendingStatusAction, error)
+
+	// CancelPendingAction cancels a scheduled status change that has not been applied yet
+	CancelPendingAction(ctx context.Context, uuid, actionID, tenantID string) (*models.PendingStatusAction, error)
+
+	// ApplyDueStatusActions applies scheduled status changes that are due and returns how many were applied
+	ApplyDueStatusActions(ctx context.Context, now time.Time) (int, error)
 }
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"hrms/internal/models"
+	"hrms/pkg/errors"
+)
+
+// resolveCandidateLimit caps how many employees a resolve returns
+const resolveCandidateLimit = 100
+
+// roundRobin hands out positions in turn per key. State is kept in memory, so each
+// service instance rotates independently.
+type roundRobin struct {
+	mu   sync.Mutex
+	next map[string]int
+}
+
+func newRoundRobin() *roundRobin {
+	return &roundRobin{next: make(map[string]int)}
+}
+
+// pick returns the next position for key in a list of n items
+func (r *roundRobin) pick(key string, n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.next[key] % n
+	r.next[key] = i + 1
+	return i
+}
+
+// ResolveEmployees finds the active employees with an active jurisdiction over the boundary
+// or any of its ancestors, optionally filtered by department and designation. The boundary
+// hierarchy is walked one level at a time from the boundary upwards until
+// resolveCandidateLimit candidates are found, so employees of closer boundaries are never
+// crowded out by those of wider ones. Candidates are ranked by how close their
+// jurisdiction is to the boundary; a strategy picks one of the closest.
+func (s *employeeService) ResolveEmployees(ctx context.Context, criteria *models.ResolveCriteria) (*models.ResolveResponse, error) {
+	if err := validateResolveCriteria(criteria); err != nil {
+		return nil, err
+	}
+
+	path, err := s.jurisdictionSvc.BoundaryPath(ctx, criteria.Boundary, criteria.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Distance from the requested boundary, which is last in the path
+	distance := make(map[string]int, len(path))
+	for i, code := range path {
+		distance[code] = len(path) - 1 - i
+	}
+
+	now := time.Now()
+	candidates := make([]*models.ResolvedEmployee, 0)
+	seen := make(map[string]bool)
+	for level := len(path) - 1; level >= 0 && len(candidates) < resolveCandidateLimit; level-- {
+		// Page through the level, as employees already found closer take up places in it
+		for offset := 0; len(candidates) < resolveCandidateLimit; offset += resolveCandidateLimit {
+			found, err := s.SearchEmployees(ctx, resolveSearch(criteria, path[level], offset))
+			if err != nil {
+				return nil, err
+			}
+
+			for _, emp := range found.Employees {
+				if len(candidates) == resolveCandidateLimit {
+					break
+				}
+				if seen[emp.ID] {
+					continue
+				}
+				var best *models.ResolvedEmployee
+				for _, j := range emp.Jurisdictions {
+					d, ok := distance[j.Boundary]
+					if !ok || !holdsAt(j, now) {
+						continue
+					}
+					if best == nil || d < best.Distance {
+						best = &models.ResolvedEmployee{Employee: emp, Boundary: j.Boundary, Distance: d}
+					}
+				}
+				if best != nil {
+					seen[emp.ID] = true
+					candidates = append(candidates, best)
+				}
+			}
+			if len(found.Employees) < resolveCandidateLimit {
+				break
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Distance < candidates[j].Distance
+	})
+
+	return &models.ResolveResponse{
+		Boundary:   criteria.Boundary,
+		Candidates: candidates,
+		Selected:   s.selectCandidate(criteria, candidates, now),
+	}, nil
+}
+
+// resolveSearch builds the search for a page of the active employees with a jurisdiction
+// over one boundary of the path
+func resolveSearch(criteria *models.ResolveCriteria, boundary string, offset int) *models.EmployeeSearchCriteria {
+	active := true
+	search := &models.EmployeeSearchCriteria{
+		BoundaryCodes: []string{boundary},
+		IsActive:      &active,
+		Limit:         resolveCandidateLimit,
+		Offset:        offset,
+		SortBy:        "code",
+		SortOrder:     "asc",
+		TenantID:      criteria.TenantID,
+	}
+	if criteria.Department != "" {
+		search.Departments = []string{criteria.Department}
+	}
+	if criteria.Designation != "" {
+		search.Designations = []string{criteria.Designation}
+	}
+	return search
+}
+
+// selectCandidate applies the selection strategy to the closest candidates
+func (s *employeeService) selectCandidate(criteria *models.ResolveCriteria, candidates []*models.ResolvedEmployee, now time.Time) *models.ResolvedEmployee {
+	if criteria.Strategy == "" || len(candidates) == 0 {
+		return nil
+	}
+
+	closest := candidates
+	for i, c := range candidates {
+		if c.Distance != candidates[0].Distance {
+			closest = candidates[:i]
+			break
+		}
+	}
+
+	switch criteria.Strategy {
+	case models.ResolveStrategyRoundRobin:
+		key := strings.Join([]string{criteria.TenantID, closest[0].Boundary, criteria.Department, criteria.Designation}, "|")
+		return closest[s.roundRobin.pick(key, len(closest))]
+
+	case models.ResolveStrategyLeastLoaded:
+		// Load is the number of jurisdictions an employee holds now
+		selected, selectedLoad := closest[0], activeJurisdictions(closest[0].Employee, now)
+		for _, c := range closest[1:] {
+			if load := activeJurisdictions(c.Employee, now); load < selectedLoad {
+				selected, selectedLoad = c, load
+			}
+		}
+		return selected
+	}
+
+	return nil
+}
+
+// activeJurisdictions counts the jurisdictions an employee holds at now
+func activeJurisdictions(emp *models.EmployeeResponse, now time.Time) int {
+	n := 0
+	for _, j := range emp.Jurisdictions {
+		if holdsAt(j, now) {
+			n++
+		}
+	}
+	return n
+}
+
+// holdsAt reports whether a jurisdiction is active and within its validity window at t,
+// matching the filter the employee search applies
+func holdsAt(j *models.JurisdictionResponse, t time.Time) bool {
+	if !j.IsActive {
+		return false
+	}
+	if j.ValidFrom != nil && j.ValidFrom.After(t) {
+		return false
+	}
+	return j.ValidTo == nil || j.ValidTo.After(t)
+}
+
+func validateResolveCriteria(criteria *models.ResolveCriteria) error {
+	errs := &errors.MultiError{}
+
+	if criteria.TenantID == "" {
+		errs.Add(errors.NewFieldError("tenantId", "tenant ID is required"))
+	}
+	if criteria.Boundary == "" {
+		errs.Add(errors.NewFieldError("boundary", "boundary is required"))
+	}
+
+	switch criteria.Strategy {
+	case "", models.ResolveStrategyRoundRobin, models.ResolveStrategyLeastLoaded:
+	default:
+		errs.Add(errors.NewFieldError("strategy", "strategy must be round_robin or least_loaded"))
+	}
+
+	return errs.ErrorOrNil()
+}
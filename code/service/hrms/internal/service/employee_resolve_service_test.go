@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"hrms/internal/config"
+	"hrms/internal/models"
+	"hrms/internal/repository"
+	"hrms/internal/validator"
+	"hrms/pkg/errors"
+)
+
+// resolveStore holds the employees and jurisdictions behind the resolve fakes
+type resolveStore struct {
+	employees     []*models.Employee
+	jurisdictions []*models.JurisdictionResponse
+	paths         map[string][]string
+}
+
+// resolveEmployeeRepo searches like the repository: active employees of the tenant with a
+// jurisdiction over one of the boundary codes that holds now, ordered by code
+type resolveEmployeeRepo struct {
+	repository.EmployeeRepository
+	store *resolveStore
+}
+
+func (r *resolveEmployeeRepo) Search(ctx context.Context, criteria *models.EmployeeSearchCriteria) ([]*models.Employee, int64, error) {
+	codes := make(map[string]bool, len(criteria.BoundaryCodes))
+	for _, code := range criteria.BoundaryCodes {
+		codes[code] = true
+	}
+
+	now := time.Now()
+	var found []*models.Employee
+	for _, e := range r.store.employees {
+		if e.TenantID != criteria.TenantID || (criteria.IsActive != nil && e.IsActive != *criteria.IsActive) {
+			continue
+		}
+		if len(criteria.Departments) > 0 && e.Department != criteria.Departments[0] {
+			continue
+		}
+		covers := false
+		for _, j := range r.store.jurisdictions {
+			if j.EmployeeID == e.ID && codes[j.Boundary] && holdsAt(j, now) {
+				covers = true
+			}
+		}
+		if covers {
+			found = append(found, e)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Code < found[j].Code })
+
+	total := int64(len(found))
+	if criteria.Offset >= len(found) {
+		return nil, total, nil
+	}
+	found = found[criteria.Offset:]
+	if len(found) > criteria.Limit {
+		found = found[:criteria.Limit]
+	}
+	return found, total, nil
+}
+
+// resolveJurisdictionService returns every stored jurisdiction of an employee, including
+// those that do not hold now, so ranking has to leave them out itself
+type resolveJurisdictionService struct {
+	JurisdictionService
+	store *resolveStore
+}
+
+func (s *resolveJurisdictionService) BoundaryPath(ctx context.Context, code, tenantID string) ([]string, error) {
+	if path, ok := s.store.paths[code]; ok {
+		return path, nil
+	}
+	return []string{code}, nil
+}
+
+func (s *resolveJurisdictionService) SearchJurisdictions(ctx context.Context, criteria *models.JurisdictionSearchCriteria) ([]*models.JurisdictionResponse, error) {
+	var found []*models.JurisdictionResponse
+	for _, j := range s.store.jurisdictions {
+		for _, id := range criteria.EmployeeIDs {
+			if j.EmployeeID == id {
+				found = append(found, j)
+			}
+		}
+	}
+	return found, nil
+}
+
+func newResolveService(store *resolveStore) *employeeService {
+	return &employeeService{
+		repo:            &resolveEmployeeRepo{store: store},
+		jurisdictionSvc: &resolveJurisdictionService{store: store},
+		validator:       validator.NewEmployeeValidator(nil, &config.Config{}),
+		roundRobin:      newRoundRobin(),
+	}
+}
+
+// resolveFixture builds a store from "employee@boundary" jurisdictions over the path
+// CITY > ZONE-1 > WARD-1. Employees are active and in department ENG unless listed in
+// other.
+func resolveFixture(jurisdictions []*models.JurisdictionResponse, other ...*models.Employee) *resolveStore {
+	store := &resolveStore{
+		jurisdictions: jurisdictions,
+		paths:         map[string][]string{"WARD-1": {"CITY", "ZONE-1", "WARD-1"}, "ZONE-1": {"CITY", "ZONE-1"}},
+	}
+	known := make(map[string]bool)
+	for _, e := range other {
+		store.employees = append(store.employees, e)
+		known[e.ID] = true
+	}
+	for _, j := range jurisdictions {
+		if !known[j.EmployeeID] {
+			store.employees = append(store.employees, &models.Employee{ID: j.EmployeeID, Code: j.EmployeeID, Department: "ENG", IsActive: true, TenantID: testTenant})
+			known[j.EmployeeID] = true
+		}
+	}
+	return store
+}
+
+func holding(employeeID, boundary string, from, to *time.Time) *models.JurisdictionResponse {
+	return &models.JurisdictionResponse{EmployeeID: employeeID, Boundary: boundary, IsActive: true, ValidFrom: from, ValidTo: to, TenantID: testTenant}
+}
+
+func TestResolveEmployees(t *testing.T) {
+	past := time.Now().Add(-48 * time.Hour)
+	yesterday := time.Now().Add(-24 * time.Hour)
+	tomorrow := time.Now().Add(24 * time.Hour)
+
+	// crowd holds more city-level employees than a resolve returns, all sorting before the
+	// ward-level one
+	var crowd []*models.JurisdictionResponse
+	for i := 0; i < resolveCandidateLimit+5; i++ {
+		crowd = append(crowd, holding(fmt.Sprintf("A-%03d", i), "CITY", &past, nil))
+	}
+	crowd = append(crowd, holding("Z-WARD", "WARD-1", &past, nil))
+
+	inactive := &models.Employee{ID: "off", Code: "off", Department: "ENG", TenantID: testTenant}
+	revenue := &models.Employee{ID: "rev", Code: "rev", Department: "REV", IsActive: true, TenantID: testTenant}
+
+	tests := []struct {
+		name       string
+		store      *resolveStore
+		criteria   models.ResolveCriteria
+		wantCount  int
+		wantFirst  []string // IDs and distances of the leading candidates, as "id:distance"
+		wantAbsent []string
+	}{
+		{
+			name: "ranked from the ward up",
+			store: resolveFixture([]*models.JurisdictionResponse{
+				holding("city", "CITY", &past, nil),
+				holding("zone", "ZONE-1", &past, nil),
+				holding("ward", "WARD-1", &past, nil),
+			}),
+			criteria:  models.ResolveCriteria{Boundary: "WARD-1"},
+			wantCount: 3,
+			wantFirst: []string{"ward:0", "zone:1", "city:2"},
+		},
+		{
+			name: "expired ward jurisdiction does not make a ward match",
+			store: resolveFixture([]*models.JurisdictionResponse{
+				holding("moved", "WARD-1", &past, &yesterday),
+				holding("moved", "ZONE-1", &yesterday, nil),
+				holding("ward", "WARD-1", &past, nil),
+			}),
+			criteria:  models.ResolveCriteria{Boundary: "WARD-1"},
+			wantCount: 2,
+			wantFirst: []string{"ward:0", "moved:1"},
+		},
+		{
+			name: "future ward jurisdiction does not make a ward match",
+			store: resolveFixture([]*models.JurisdictionResponse{
+				holding("incoming", "WARD-1", &tomorrow, nil),
+				holding("incoming", "CITY", &past, nil),
+			}),
+			criteria:  models.ResolveCriteria{Boundary: "WARD-1"},
+			wantCount: 1,
+			wantFirst: []string{"incoming:2"},
+		},
+		{
+			name: "employee over several levels is listed once at the closest",
+			store: resolveFixture([]*models.JurisdictionResponse{
+				holding("both", "CITY", &past, nil),
+				holding("both", "WARD-1", &past, nil),
+			}),
+			criteria:  models.ResolveCriteria{Boundary: "WARD-1"},
+			wantCount: 1,
+			wantFirst: []string{"both:0"},
+		},
+		{
+			name: "inactive employees and other departments are left out",
+			store: resolveFixture([]*models.JurisdictionResponse{
+				holding("off", "WARD-1", &past, nil),
+				holding("rev", "WARD-1", &past, nil),
+				holding("zone", "ZONE-1", &past, nil),
+			}, inactive, revenue),
+			criteria:   models.ResolveCriteria{Boundary: "WARD-1", Department: "ENG"},
+			wantCount:  1,
+			wantFirst:  []string{"zone:1"},
+			wantAbsent: []string{"off", "rev"},
+		},
+		{
+			name:      "nobody responsible",
+			store:     resolveFixture(nil),
+			criteria:  models.ResolveCriteria{Boundary: "WARD-1"},
+			wantCount: 0,
+		},
+		{
+			name:      "closer employees are not crowded out by wider ones",
+			store:     resolveFixture(crowd),
+			criteria:  models.ResolveCriteria{Boundary: "WARD-1"},
+			wantCount: resolveCandidateLimit,
+			wantFirst: []string{"Z-WARD:0", "A-000:2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newResolveService(tt.store)
+			criteria := tt.criteria
+			criteria.TenantID = testTenant
+
+			resp, err := s.ResolveEmployees(context.Background(), &criteria)
+			if err != nil {
+				t.Fatalf("ResolveEmployees: %v", err)
+			}
+			if len(resp.Candidates) != tt.wantCount {
+				t.Fatalf("got %d candidates, want %d", len(resp.Candidates), tt.wantCount)
+			}
+			for i, want := range tt.wantFirst {
+				c := resp.Candidates[i]
+				if got := fmt.Sprintf("%s:%d", c.Employee.ID, c.Distance); got != want {
+					t.Fatalf("candidate %d = %s, want %s", i, got, want)
+				}
+			}
+			for _, c := range resp.Candidates {
+				for _, id := range tt.wantAbsent {
+					if c.Employee.ID == id {
+						t.Fatalf("candidate %s should have been left out", id)
+					}
+				}
+			}
+			if resp.Selected != nil {
+				t.Fatalf("selected %s without a strategy", resp.Selected.Employee.ID)
+			}
+		})
+	}
+}
+
+func TestResolveStrategy(t *testing.T) {
+	past := time.Now().Add(-48 * time.Hour)
+	yesterday := time.Now().Add(-24 * time.Hour)
+
+	// busy and quiet both cover the ward; busy holds two more jurisdictions now, quiet
+	// held many that have ended
+	jurisdictions := []*models.JurisdictionResponse{
+		holding("busy", "WARD-1", &past, nil),
+		holding("busy", "WARD-2", &past, nil),
+		holding("busy", "WARD-3", &past, nil),
+		holding("quiet", "WARD-1", &past, nil),
+		holding("zone", "ZONE-1", &past, nil),
+	}
+	for i := 0; i < 5; i++ {
+		jurisdictions = append(jurisdictions, holding("quiet", fmt.Sprintf("OLD-%d", i), &past, &yesterday))
+	}
+
+	tests := []struct {
+		name         string
+		strategy     string
+		calls        int
+		wantSelected []string
+		wantInvalid  bool
+	}{
+		{name: "no strategy selects nobody", calls: 1, wantSelected: []string{""}},
+		{name: "round robin rotates over the closest", strategy: models.ResolveStrategyRoundRobin, calls: 3, wantSelected: []string{"busy", "quiet", "busy"}},
+		{name: "least loaded counts only jurisdictions held now", strategy: models.ResolveStrategyLeastLoaded, calls: 2, wantSelected: []string{"quiet", "quiet"}},
+		{name: "unknown strategy", strategy: "random", calls: 1, wantInvalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newResolveService(resolveFixture(jurisdictions))
+
+			for i := 0; i < tt.calls; i++ {
+				criteria := &models.ResolveCriteria{Boundary: "WARD-1", Strategy: tt.strategy, TenantID: testTenant}
+				resp, err := s.ResolveEmployees(context.Background(), criteria)
+				if tt.wantInvalid {
+					if !errors.Is(err, errors.ErrValidationFailed) {
+						t.Fatalf("error = %v, want a validation error", err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("ResolveEmployees: %v", err)
+				}
+
+				var selected string
+				if resp.Selected != nil {
+					selected = resp.Selected.Employee.ID
+					if resp.Selected.Distance != 0 {
+						t.Fatalf("selected %s at distance %d, want one of the closest", selected, resp.Selected.Distance)
+					}
+				}
+				if selected != tt.wantSelected[i] {
+					t.Fatalf("call %d selected %q, want %q", i, selected, tt.wantSelected[i])
+				}
+			}
+		})
+	}
+}
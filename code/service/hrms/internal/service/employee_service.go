@@ -5,29 +5,83 @@ import (
 	"time"
 
 	"hrms/internal/clients/idgen"
+	"hrms/internal/clients/individual"
+	"hrms/internal/clients/user"
 	"hrms/internal/models"
 	"hrms/internal/repository"
+	"hrms/internal/validator"
 	"hrms/pkg/errors"
 
 	"github.com/sirupsen/logrus"
 )
 
 type employeeService struct {
-	repo            repository.EmployeeRepository
-	jurisdictionSvc JurisdictionService
-	idGenClient     idgen.Client
+	repo              repository.EmployeeRepository
+	jurisdictionSvc   JurisdictionService
+	idGenClient       idgen.Client
+	assignmentSvc     AssignmentService
+	historySvc        ServiceHistoryService
+	qualificationSvc  QualificationService
+	deactivationRepo  repository.DeactivationRepository
+	pendingActionRepo repository.PendingActionRepository
+	validator         *validator.EmployeeValidator
+	uow               repository.UnitOfWork
+	individualClient  individual.Client
+	userClient        user.Client
+	passwordGenerator *user.PasswordGenerator
+	auditRepo         repository.AuditRepository
+	roundRobin        *roundRobin
 }
 
 // NewEmployeeService creates a new employee service
-func NewEmployeeService(repo repository.EmployeeRepository, jurisdictionSvc JurisdictionService, idGenClient idgen.Client) EmployeeService {
+func NewEmployeeService(repo repository.EmployeeRepository, jurisdictionSvc JurisdictionService, idGenClient idgen.Client, assignmentSvc AssignmentService, historySvc ServiceHistoryService, qualificationSvc QualificationService, deactivationRepo repository.DeactivationRepository, pendingActionRepo repository.PendingActionRepository, employeeValidator *validator.EmployeeValidator, uow repository.UnitOfWork, individualClient individual.Client, userClient user.Client, passwordGenerator *user.PasswordGenerator, auditRepo repository.AuditRepository) EmployeeService {
 
 	return &employeeService{
-		repo:            repo,
-		jurisdictionSvc: jurisdictionSvc,
-		idGenClient:     idGenClient,
+		repo:              repo,
+		jurisdictionSvc:   jurisdictionSvc,
+		idGenClient:       idGenClient,
+		assignmentSvc:     assignmentSvc,
+		historySvc:        historySvc,
+		qualificationSvc:  qualificationSvc,
+		deactivationRepo:  deactivationRepo,
+		pendingActionRepo: pendingActionRepo,
+		validator:         employeeValidator,
+		uow:               uow,
+		individualClient:  individualClient,
+		userClient:        userClient,
+		passwordGenerator: passwordGenerator,
+		auditRepo:         auditRepo,
+		roundRobin:        newRoundRobin(),
 	}
 }
 
+// withItemIndex records the position of the failing item of a bulk request on the error
+func withItemIndex(err error, index int) error {
+	errs := &errors.MultiError{}
+	errs.Add(err)
+
+	annotated := &errors.MultiError{}
+	for _, e := range errs.Errors {
+		annotated.Add(e.WithParam("index", index))
+	}
+	if len(annotated.Errors) == 1 {
+		return annotated.Errors[0]
+	}
+	return annotated
+}
+
+// toModelErrors converts a service error into the API error representation
+func toModelErrors(err error) []*models.Error {
+	errs := &errors.MultiError{}
+	errs.Add(err)
+
+	result := make([]*models.Error, 0, len(errs.Errors))
+	for _, e := range errs.Errors {
+		result = append(result, &models.Error{Code: e.Code, Message: e.Message, Description: e.Description, Params: e.Params})
+	}
+	return result
+}
+
 // generateEmployeeCode generates a new employee code using the ID generation service
 func (s *employeeService) generateEmployeeCode(ctx context.Context, tenantID string) (string, error) {
 	ids, err := s.idGenClient.GenerateIDs(ctx, tenantID, 1, nil)
@@ -41,20 +95,34 @@ func (s *employeeService) generateEmployeeCode(ctx context.Context, tenantID str
 }
 
 // toEmployeeResponse converts an Employee model to EmployeeResponse
-func toEmployeeResponse(emp *models.Employee, jurisdictionSvc JurisdictionService, tenantID string) (*models.EmployeeResponse, error) {
+func (s *employeeService) toEmployeeResponse(ctx context.Context, emp *models.Employee, tenantID string) (*models.EmployeeResponse, error) {
+	return s.toEmployeeResponseAsOf(ctx, emp, tenantID, nil, s.individualsOf(ctx, tenantID, emp))
+}
+
+// toEmployeeResponseAsOf converts an Employee model read as of a past time, taking its
+// jurisdictions from the same time; a nil asOf reads the current ones. The other details
+// are always the current ones. individuals holds the employee's Individual details by ID,
+// fetched by the caller so a page of employees needs a single lookup.
+func (s *employeeService) toEmployeeResponseAsOf(ctx context.Context, emp *models.Employee, tenantID string, asOf *time.Time, individuals map[string]*models.IndividualDetails) (*models.EmployeeResponse, error) {
 	if emp == nil {
 		return nil, nil
 	}
 
 	// Get jurisdictions for the employee
 	var jurisdictions []*models.JurisdictionResponse
-	if jurisdictionSvc != nil {
-		// Search for jurisdictions by employee ID
+	if s.jurisdictionSvc != nil {
+		// Search for the jurisdictions of the employee that hold at that time; closed ones stay out
+		at := time.Now()
+		if asOf != nil {
+			at = *asOf
+		}
 		criteria := &models.JurisdictionSearchCriteria{
 			EmployeeIDs: []string{emp.ID},
+			AsOf:        &at,
+			RecordedAt:  asOf,
 			TenantID:    tenantID,
 		}
-		jurs, err := jurisdictionSvc.SearchJurisdictions(context.Background(), criteria)
+		jurs, err := s.jurisdictionSvc.SearchJurisdictions(ctx, criteria)
 		if err != nil {
 			logrus.WithError(err).Error("Failed to fetch jurisdictions for employee")
 			// Continue without jurisdictions if there's an error
@@ -62,6 +130,69 @@ func toEmployeeResponse(emp *models.Employee, jurisdictionSvc JurisdictionServic
 		jurisdictions = jurs
 	}
 
+	// Get assignments for the employee
+	var assignments []*models.Assignment
+	if s.assignmentSvc != nil {
+		asgs, err := s.assignmentSvc.GetAssignmentsByEmployeeID(ctx, emp.ID, tenantID)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to fetch assignments for employee")
+			// Continue without assignments if there's an error
+		}
+		assignments = asgs
+	}
+
+	// Get service history for the employee
+	var serviceHistory []*models.ServiceHistory
+	if s.historySvc != nil {
+		history, err := s.historySvc.GetServiceHistoryByEmployeeID(ctx, emp.ID, tenantID)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to fetch service history for employee")
+			// Continue without service history if there's an error
+		}
+		serviceHistory = history
+	}
+
+	// Get educational details and departmental tests for the employee
+	var education []*models.EducationalDetail
+	var tests []*models.DepartmentalTest
+	if s.qualificationSvc != nil {
+		edu, err := s.qualificationSvc.GetEducationalDetailsByEmployeeID(ctx, emp.ID, tenantID)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to fetch educational details for employee")
+			// Continue without educational details if there's an error
+		}
+		education = edu
+
+		dts, err := s.qualificationSvc.GetDepartmentalTestsByEmployeeID(ctx, emp.ID, tenantID)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to fetch departmental tests for employee")
+			// Continue without departmental tests if there's an error
+		}
+		tests = dts
+	}
+
+	// Get deactivation and reactivation history for the employee
+	var statusHistory []*models.StatusHistory
+	if s.deactivationRepo != nil {
+		history, err := s.deactivationRepo.FindByEmployeeID(ctx, emp.ID, tenantID)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to fetch status history for employee")
+			// Continue without status history if there's an error
+		}
+		statusHistory = history
+	}
+
+	// Get scheduled status changes that have not been applied yet
+	var pendingActions []*models.PendingStatusAction
+	if s.pendingActionRepo != nil {
+		actions, err := s.pendingActionRepo.FindByEmployeeID(ctx, emp.ID, models.PendingActionStatusPending, tenantID)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to fetch pending status actions for employee")
+			// Continue without pending actions if there's an error
+		}
+		pendingActions = actions
+	}
+
 	return &models.EmployeeResponse{
 		ID:                emp.ID,
 		Code:              emp.Code,
@@ -73,97 +204,281 @@ func toEmployeeResponse(emp *models.Employee, jurisdictionSvc JurisdictionServic
 		Department:        emp.Department,
 		Designation:       emp.Designation,
 		IsActive:          emp.IsActive,
+		MobileNumber:      emp.MobileNumber,
+		Email:             emp.Email,
+		DateOfBirth:       emp.DateOfBirth,
+		Gender:            emp.Gender,
+		PanNumber:         emp.PanNumber,
+		AadhaarNumber:     emp.AadhaarNumber,
+		DateOfRetirement:  emp.DateOfRetirement,
+		Individual:        individuals[emp.IndividualID],
 		Jurisdictions:     jurisdictions,
+		Assignments:       assignments,
+		ServiceHistory:    serviceHistory,
+		Education:         education,
+		Tests:             tests,
+		StatusHistory:     statusHistory,
+		PendingActions:    pendingActions,
+		AuditDetails: &models.AuditDetails{
+			CreatedBy:        emp.CreatedBy,
+			LastModifiedBy:   emp.LastModifiedBy,
+			CreatedTime:      emp.CreatedTime,
+			LastModifiedTime: emp.LastModifiedTime,
+		},
 	}, nil
 }
 
-// CreateEmployees creates one or more employees
+// CreateEmployees creates one or more employees. The batch is atomic: if any employee
+// fails, nothing is written and the login accounts created for the batch are removed.
 func (s *employeeService) CreateEmployees(ctx context.Context, req []*models.CreateEmployeeRequest, tenantID string) ([]*models.EmployeeResponse, error) {
-	responses := make([]*models.EmployeeResponse, 0, len(req))
+	created := make([]*models.Employee, 0, len(req))
+	passwords := make([]string, 0, len(req))
 
-	for _, r := range req {
-		// Generate employee code
-		code, err := s.generateEmployeeCode(ctx, tenantID)
-		if err != nil {
-			logrus.WithError(err).Error("Failed to generate employee code")
-			return nil, errors.Wrap(err, "ID_GENERATION_ERROR", "failed to generate employee code").WithOperation("CreateEmployees")
-		}
-
-		// Map request to employee model
-		now := time.Now().Unix()
-		employee := &models.Employee{
-			ID:                "", // Will be generated by the database
-			Code:              code,
-			UserID:            r.UserID,
-			IndividualID:      r.IndividualID,
-			Status:            r.Status,
-			EmployeeType:      r.EmployeeType,
-			DateOfAppointment: r.DateOfAppointment,
-			Department:        r.Department,
-			Designation:       r.Designation,
-			IsActive:          true,
-			TenantID:          tenantID,
-			CreatedBy:         "system", // TODO: Replace with actual user from context
-			CreatedTime:       now,
-		}
-
-		// Set IsActive from request if provided
-		if r.IsActive != nil {
-			employee.IsActive = *r.IsActive
-		}
-
-		// Save to database
-		if err := s.repo.Create(ctx, employee); err != nil {
-			logrus.WithError(err).Error("Failed to create employee")
-			return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to create employee").WithOperation("CreateEmployees")
-		}
-
-		// Create jurisdictions if provided
-		if len(r.Jurisdictions) > 0 {
-			for _, j := range r.Jurisdictions {
-				jurisReq := &models.CreateJurisdictionRequest{
-					EmployeeID:       employee.ID,
-					BoundaryRelation: j.BoundaryRelation,
-					IsActive:         &j.IsActive,
-				}
-
-				_, err := s.jurisdictionSvc.CreateJurisdiction(ctx, jurisReq, tenantID)
-				if err != nil {
-					logrus.WithError(err).Error("Failed to create jurisdiction for employee")
-					// Continue with employee creation even if jurisdiction creation fails
-				}
+	var undo compensations
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		for i, r := range req {
+			employee, password, err := s.createEmployee(ctx, r, tenantID, &undo)
+			if err != nil {
+				return withItemIndex(err, i)
 			}
+			created = append(created, employee)
+			passwords = append(passwords, password)
 		}
+		return nil
+	})
+	if err != nil {
+		undo.run(ctx)
+		return nil, err
+	}
 
-		// Get the employee with jurisdictions
-		temp, err := s.repo.FindByUUID(ctx, employee.ID, tenantID)
-		if err != nil {
-			logrus.WithError(err).Error("Failed to fetch created employee")
-			return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to fetch created employee").WithOperation("CreateEmployees")
-		}
-
-		resp, err := toEmployeeResponse(temp, s.jurisdictionSvc, tenantID)
+	individuals := s.individualsOf(ctx, tenantID, created...)
+	responses := make([]*models.EmployeeResponse, 0, len(created))
+	for i, employee := range created {
+		resp, err := s.toEmployeeResponseAsOf(ctx, employee, tenantID, nil, individuals)
 		if err != nil {
 			logrus.WithError(err).Error("Failed to convert employee to response")
 			continue
 		}
+		resp.InitialPassword = passwords[i]
 		responses = append(responses, resp)
 	}
 
 	return responses, nil
 }
 
+// CreateEmployeesPartial creates each employee in its own transaction and reports the
+// outcome per item, so valid employees are kept even when others in the batch fail
+func (s *employeeService) CreateEmployeesPartial(ctx context.Context, req []*models.CreateEmployeeRequest, tenantID string) []*models.EmployeeBulkResult {
+	results := make([]*models.EmployeeBulkResult, 0, len(req))
+
+	for i, r := range req {
+		var employee *models.Employee
+		var password string
+		var undo compensations
+		err := s.uow.Do(ctx, func(ctx context.Context) error {
+			var err error
+			employee, password, err = s.createEmployee(ctx, r, tenantID, &undo)
+			return err
+		})
+		if err != nil {
+			undo.run(ctx)
+			results = append(results, &models.EmployeeBulkResult{Index: i, Success: false, Errors: toModelErrors(err)})
+			continue
+		}
+
+		resp, err := s.toEmployeeResponse(ctx, employee, tenantID)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to convert employee to response")
+		}
+		if resp != nil {
+			resp.InitialPassword = password
+		}
+		results = append(results, &models.EmployeeBulkResult{Index: i, Success: true, Employee: resp})
+	}
+
+	return results
+}
+
+// createEmployee validates and writes a single employee with all of its child records,
+// and returns the password of the login account created for it, if any. Callers are
+// expected to run it inside a unit of work and to run undo if it fails.
+func (s *employeeService) createEmployee(ctx context.Context, r *models.CreateEmployeeRequest, tenantID string, undo *compensations) (*models.Employee, string, error) {
+	// Map request to employee model
+	now := time.Now().Unix()
+	employee := &models.Employee{
+		ID:                "", // Will be generated by the database
+		UserID:            r.UserID,
+		IndividualID:      r.IndividualID,
+		Status:            r.Status,
+		EmployeeType:      r.EmployeeType,
+		DateOfAppointment: r.DateOfAppointment,
+		Department:        r.Department,
+		Designation:       r.Designation,
+		IsActive:          true,
+		MobileNumber:      r.MobileNumber,
+		Email:             r.Email,
+		DateOfBirth:       r.DateOfBirth,
+		Gender:            r.Gender,
+		PanNumber:         r.PanNumber,
+		AadhaarNumber:     r.AadhaarNumber,
+		DateOfRetirement:  r.DateOfRetirement,
+		TenantID:          tenantID,
+		CreatedTime:       now,
+	}
+
+	// Set IsActive from request if provided
+	if r.IsActive != nil {
+		employee.IsActive = *r.IsActive
+	}
+
+	// Validate the employee and its child records before anything is written
+	if err := s.validateEmployee(ctx, employee, nil, r); err != nil {
+		return nil, "", err
+	}
+
+	// Link the employee to its Individual record
+	individualID, err := s.resolveIndividual(ctx, r, tenantID)
+	if err != nil {
+		return nil, "", err
+	}
+	employee.IndividualID = individualID
+
+	// Generate employee code
+	code, err := s.generateEmployeeCode(ctx, tenantID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to generate employee code")
+		return nil, "", errors.Wrap(err, "ID_GENERATION_ERROR", "failed to generate employee code").WithOperation("CreateEmployees")
+	}
+	employee.Code = code
+
+	// Create the login account, named after the employee code
+	password, err := s.createUserAccount(ctx, employee, r, undo)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Save to database
+	if err := s.repo.Create(ctx, employee); err != nil {
+		logrus.WithError(err).Error("Failed to create employee")
+		return nil, "", errors.Wrap(err, "DATABASE_ERROR", "failed to create employee").WithOperation("CreateEmployees")
+	}
+
+	if err := s.createJurisdictions(ctx, employee.ID, r.Jurisdictions, tenantID); err != nil {
+		return nil, "", err
+	}
+
+	// Create assignments if provided
+	if len(r.Assignments) > 0 {
+		if _, err := s.assignmentSvc.CreateAssignments(ctx, employee.ID, r.Assignments, tenantID); err != nil {
+			logrus.WithError(err).Error("Failed to create assignments for employee")
+			return nil, "", err
+		}
+	}
+
+	// Create service history if provided
+	if len(r.ServiceHistory) > 0 {
+		if _, err := s.historySvc.CreateServiceHistory(ctx, employee.ID, r.ServiceHistory, tenantID); err != nil {
+			logrus.WithError(err).Error("Failed to create service history for employee")
+			return nil, "", err
+		}
+	}
+
+	// Create educational details and departmental tests if provided
+	if len(r.Education) > 0 || len(r.Tests) > 0 {
+		if err := s.qualificationSvc.CreateQualifications(ctx, employee.ID, r.Education, r.Tests, tenantID); err != nil {
+			logrus.WithError(err).Error("Failed to create qualifications for employee")
+			return nil, "", err
+		}
+	}
+
+	// Reload to pick up database defaults
+	created, err := s.repo.FindByUUID(ctx, employee.ID, tenantID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch created employee")
+		return nil, "", errors.Wrap(err, "DATABASE_ERROR", "failed to fetch created employee").WithOperation("CreateEmployees")
+	}
+
+	return created, password, nil
+}
+
+// validateEmployee runs the employee validator together with the child record checks
+// and reports every failure at once. existing is nil for creates.
+func (s *employeeService) validateEmployee(ctx context.Context, employee, existing *models.Employee, r *models.CreateEmployeeRequest) error {
+	errs := &errors.MultiError{}
+	if existing == nil {
+		errs.Add(s.validator.ValidateCreate(ctx, employee))
+	} else {
+		errs.Add(s.validator.ValidateUpdate(ctx, employee, existing))
+	}
+	errs.Add(s.assignmentSvc.ValidateAssignments(r.Assignments))
+	errs.Add(s.historySvc.ValidateServiceHistory(r.ServiceHistory))
+	errs.Add(s.qualificationSvc.ValidateQualifications(r.Education, r.Tests))
+	return errs.ErrorOrNil()
+}
+
+// createJurisdictions creates the given jurisdictions for an employee
+func (s *employeeService) createJurisdictions(ctx context.Context, employeeID string, jurisdictions []*models.Jurisdiction, tenantID string) error {
+	for _, jurisReq := range toJurisdictionRequests(employeeID, jurisdictions) {
+		if _, err := s.jurisdictionSvc.CreateJurisdiction(ctx, jurisReq, tenantID); err != nil {
+			logrus.WithError(err).Error("Failed to create jurisdiction for employee")
+			return err
+		}
+	}
+	return nil
+}
+
+// toJurisdictionRequests maps the jurisdictions of an employee request to create requests
+func toJurisdictionRequests(employeeID string, jurisdictions []*models.Jurisdiction) []*models.CreateJurisdictionRequest {
+	reqs := make([]*models.CreateJurisdictionRequest, 0, len(jurisdictions))
+	for _, j := range jurisdictions {
+		isActive := j.IsActive
+		reqs = append(reqs, &models.CreateJurisdictionRequest{
+			EmployeeID:       employeeID,
+			Hierarchy:        j.Hierarchy,
+			BoundaryType:     j.BoundaryType,
+			Boundary:         j.Boundary,
+			BoundaryRelation: j.BoundaryRelation,
+			ValidFrom:        j.ValidFrom,
+			ValidTo:          j.ValidTo,
+			IsActive:         &isActive,
+		})
+	}
+	return reqs
+}
+
+// deleteJurisdictions deletes all jurisdictions of an employee
+func (s *employeeService) deleteJurisdictions(ctx context.Context, employeeID, tenantID string) error {
+	jurs, err := s.jurisdictionSvc.SearchJurisdictions(ctx, &models.JurisdictionSearchCriteria{EmployeeIDs: []string{employeeID}, TenantID: tenantID})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch jurisdictions for deletion")
+		return err
+	}
+
+	for _, jur := range jurs {
+		if err := s.jurisdictionSvc.DeleteJurisdiction(ctx, jur.ID, tenantID); err != nil {
+			logrus.WithError(err).Error("Failed to delete jurisdiction")
+			return err
+		}
+	}
+	return nil
+}
+
 // SearchEmployees searches for employees based on criteria
-func (s *employeeService) SearchEmployees(ctx context.Context, criteria *models.EmployeeSearchCriteria) ([]*models.EmployeeResponse, error) {
-	employees, err := s.repo.Search(ctx, criteria)
+func (s *employeeService) SearchEmployees(ctx context.Context, criteria *models.EmployeeSearchCriteria) (*models.EmployeeSearchResponse, error) {
+	if err := s.validator.ValidateSearch(ctx, criteria); err != nil {
+		return nil, err
+	}
+
+	employees, total, err := s.repo.Search(ctx, criteria)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to search employees")
 		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to search employees").WithOperation("SearchEmployees")
 	}
 
+	individuals := s.individualsOf(ctx, criteria.TenantID, employees...)
 	responses := make([]*models.EmployeeResponse, 0, len(employees))
 	for _, emp := range employees {
-		resp, err := toEmployeeResponse(emp, s.jurisdictionSvc, criteria.TenantID)
+		resp, err := s.toEmployeeResponseAsOf(ctx, emp, criteria.TenantID, criteria.AsOf, individuals)
 		if err != nil {
 			logrus.WithError(err).Error("Failed to convert employee to response")
 			continue
@@ -171,7 +486,27 @@ func (s *employeeService) SearchEmployees(ctx context.Context, criteria *models.
 		responses = append(responses, resp)
 	}
 
-	return responses, nil
+	return &models.EmployeeSearchResponse{
+		Employees:  responses,
+		TotalCount: total,
+		Limit:      criteria.Limit,
+		Offset:     criteria.Offset,
+	}, nil
+}
+
+// CountEmployees returns employee totals matching the criteria
+func (s *employeeService) CountEmployees(ctx context.Context, criteria *models.EmployeeSearchCriteria) (*models.EmployeeCountResponse, error) {
+	if err := s.validator.ValidateSearch(ctx, criteria); err != nil {
+		return nil, err
+	}
+
+	counts, err := s.repo.Count(ctx, criteria)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to count employees")
+		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to count employees").WithOperation("CountEmployees")
+	}
+
+	return counts, nil
 }
 
 // GetEmployeeByUUID retrieves an employee by UUID
@@ -185,10 +520,25 @@ func (s *employeeService) GetEmployeeByUUID(ctx context.Context, uuid, tenantID
 		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to get employee").WithOperation("GetEmployeeByUUID")
 	}
 
-	return toEmployeeResponse(employee, s.jurisdictionSvc, tenantID)
+	return s.toEmployeeResponse(ctx, employee, tenantID)
 }
 
-// UpdateEmployee (PUT) replaces an employee by UUID
+// GetEmployeeAsOf retrieves an employee as it was at the given time
+func (s *employeeService) GetEmployeeAsOf(ctx context.Context, uuid, tenantID string, asOf time.Time) (*models.EmployeeResponse, error) {
+	employee, err := s.repo.FindByUUIDAsOf(ctx, uuid, tenantID, asOf)
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return nil, errors.ErrNotFound.WithDescription("employee not found at the given time").WithOperation("GetEmployeeAsOf")
+		}
+		logrus.WithError(err).Error("Failed to get employee as of time")
+		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to get employee").WithOperation("GetEmployeeAsOf")
+	}
+
+	return s.toEmployeeResponseAsOf(ctx, employee, tenantID, &asOf, s.individualsOf(ctx, tenantID, employee))
+}
+
+// UpdateEmployee (PUT) replaces an employee by UUID. Jurisdictions, service history,
+// education and tests are replaced only when given; assignments are merged.
 func (s *employeeService) UpdateEmployee(ctx context.Context, uuid string, req *models.CreateEmployeeRequest, tenantID string) (*models.EmployeeResponse, error) {
 	// Get existing employee
 	existing, err := s.repo.FindByUUID(ctx, uuid, tenantID)
@@ -200,90 +550,131 @@ func (s *employeeService) UpdateEmployee(ctx context.Context, uuid string, req *
 		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to find employee").WithOperation("UpdateEmployee")
 	}
 
-	// 1. Delete existing jurisdictions
-	jurs, err := s.jurisdictionSvc.SearchJurisdictions(ctx, &models.JurisdictionSearchCriteria{EmployeeIDs: []string{existing.ID}, TenantID: tenantID})
-	if err != nil {
-		logrus.WithError(err).Error("Failed to fetch jurisdictions for deletion")
-	} else {
-		for _, jur := range jurs {
-			if err := s.jurisdictionSvc.DeleteJurisdiction(ctx, jur.ID, tenantID); err != nil {
-				logrus.WithError(err).Error("Failed to delete jurisdiction")
-			}
-		}
+	// Replace fields on a copy so the validator can compare against the stored employee
+	// Identifiers left out of the request keep their stored values
+	updated := *existing
+	if req.Code != "" {
+		updated.Code = req.Code
+	}
+	if req.UserID != "" {
+		updated.UserID = req.UserID
+	}
+	if req.IndividualID != "" {
+		updated.IndividualID = req.IndividualID
+	}
+	updated.Status = req.Status
+	updated.EmployeeType = req.EmployeeType
+	updated.DateOfAppointment = req.DateOfAppointment
+	updated.Department = req.Department
+	updated.Designation = req.Designation
+	updated.MobileNumber = req.MobileNumber
+	updated.Email = req.Email
+	updated.DateOfBirth = req.DateOfBirth
+	updated.Gender = req.Gender
+	updated.PanNumber = req.PanNumber
+	updated.AadhaarNumber = req.AadhaarNumber
+	updated.DateOfRetirement = req.DateOfRetirement
+	if req.IsActive != nil {
+		updated.IsActive = *req.IsActive
+	}
+
+	// Update the last_modified_time
+	now := time.Now().Unix()
+	updated.LastModifiedTime = &now
+
+	if err := s.validateEmployee(ctx, &updated, existing, req); err != nil {
+		return nil, err
+	}
+	if err := s.validateIndividualID(ctx, updated.IndividualID, tenantID); err != nil {
+		return nil, err
 	}
 
-	// 2. Create new jurisdictions
-	if len(req.Jurisdictions) > 0 {
-		for _, j := range req.Jurisdictions {
-			jurisReq := &models.CreateJurisdictionRequest{
-				EmployeeID:       existing.ID,
-				BoundaryRelation: j.BoundaryRelation,
-				IsActive:         &j.IsActive,
+	// Child lists left out of the request (nil) are kept as stored; an empty list clears them
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		// Assignments are merged rather than replaced, so validate and apply them first
+		if req.Assignments != nil {
+			if _, err := s.assignmentSvc.ReplaceAssignments(ctx, existing.ID, req.Assignments, tenantID); err != nil {
+				logrus.WithError(err).Error("Failed to replace assignments for employee")
+				return err
 			}
+		}
 
-			_, err := s.jurisdictionSvc.CreateJurisdiction(ctx, jurisReq, tenantID)
-			if err != nil {
-				logrus.WithError(err).Error("Failed to create jurisdiction for employee")
-				// Continue with employee creation even if jurisdiction creation fails
+		if req.ServiceHistory != nil {
+			if _, err := s.historySvc.ReplaceServiceHistory(ctx, existing.ID, req.ServiceHistory, tenantID); err != nil {
+				logrus.WithError(err).Error("Failed to replace service history for employee")
+				return err
 			}
 		}
-	}
 
-	// Replace fields
-	existing.Code = req.Code
-	existing.UserID = req.UserID
-	existing.IndividualID = req.IndividualID
-	existing.Status = req.Status
-	existing.EmployeeType = req.EmployeeType
-	existing.DateOfAppointment = req.DateOfAppointment
-	existing.Department = req.Department
-	existing.Designation = req.Designation
-	if req.IsActive != nil {
-		existing.IsActive = *req.IsActive
-	}
+		if req.Education != nil || req.Tests != nil {
+			if err := s.qualificationSvc.ReplaceQualifications(ctx, existing.ID, req.Education, req.Tests, tenantID); err != nil {
+				logrus.WithError(err).Error("Failed to replace qualifications for employee")
+				return err
+			}
+		}
 
-	// Update the last_modified_time
-	now := time.Now().Unix()
-	existing.LastModifiedTime = &now
+		// Replace jurisdictions
+		if req.Jurisdictions != nil {
+			if _, err := s.jurisdictionSvc.ReplaceEmployeeJurisdictions(ctx, existing.ID, toJurisdictionRequests(existing.ID, req.Jurisdictions), tenantID); err != nil {
+				logrus.WithError(err).Error("Failed to replace jurisdictions for employee")
+				return err
+			}
+		}
 
-	// Save the updated employee
-	if err := s.repo.Update(ctx, existing); err != nil {
-		logrus.WithError(err).Error("Failed to update employee")
-		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to update employee").WithOperation("UpdateEmployee")
+		// Save the updated employee
+		if err := s.repo.Update(ctx, &updated); err != nil {
+			logrus.WithError(err).Error("Failed to update employee")
+			return errors.Wrap(err, "DATABASE_ERROR", "failed to update employee").WithOperation("UpdateEmployee")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Return the updated employee
-	return toEmployeeResponse(existing, s.jurisdictionSvc, tenantID)
+	return s.toEmployeeResponse(ctx, &updated, tenantID)
 }
 
 // HardDeleteEmployee deletes an employee and their jurisdictions
 func (s *employeeService) HardDeleteEmployee(ctx context.Context, uuid, tenantID string) error {
-	// First, find and delete all jurisdictions for this employee
-	criteria := &models.JurisdictionSearchCriteria{
-		EmployeeIDs: []string{uuid},
-		TenantID:    tenantID,
-	}
-	jurs, err := s.jurisdictionSvc.SearchJurisdictions(ctx, criteria)
+	// Assignment history must never be deleted, so employees that have any cannot be removed
+	assignments, err := s.assignmentSvc.GetAssignmentsByEmployeeID(ctx, uuid, tenantID)
 	if err != nil {
-		logrus.WithError(err).Error("Failed to fetch jurisdictions for deletion")
-	} else {
-		for _, jur := range jurs {
-			if err := s.jurisdictionSvc.DeleteJurisdiction(ctx, jur.ID, tenantID); err != nil {
-				logrus.WithError(err).Error("Failed to delete jurisdiction")
-			}
-		}
+		return err
+	}
+	if len(assignments) > 0 {
+		return errors.New("EMPLOYEE_HAS_ASSIGNMENTS", "employee has assignments and cannot be deleted").
+			WithOperation("HardDeleteEmployee")
 	}
 
-	// Then delete the employee
-	if err := s.repo.Delete(ctx, uuid, tenantID); err != nil {
-		if errors.Is(err, errors.ErrNotFound) {
-			return errors.ErrNotFound.WithDescription("employee not found").WithOperation("HardDeleteEmployee")
+	// Neither may their deactivation and reactivation history
+	if s.deactivationRepo != nil {
+		history, err := s.deactivationRepo.FindByEmployeeID(ctx, uuid, tenantID)
+		if err != nil {
+			return errors.Wrap(err, "DATABASE_ERROR", "failed to find status history").WithOperation("HardDeleteEmployee")
+		}
+		if len(history) > 0 {
+			return errors.New("EMPLOYEE_HAS_STATUS_HISTORY", "employee has been deactivated or reactivated and cannot be deleted").
+				WithOperation("HardDeleteEmployee")
 		}
-		logrus.WithError(err).Error("Failed to delete employee")
-		return errors.Wrap(err, "DATABASE_ERROR", "failed to delete employee").WithOperation("HardDeleteEmployee")
 	}
 
-	return nil
+	// Delete the jurisdictions and the employee together
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.deleteJurisdictions(ctx, uuid, tenantID); err != nil {
+			return err
+		}
+
+		if err := s.repo.Delete(ctx, uuid, tenantID); err != nil {
+			if errors.Is(err, errors.ErrNotFound) {
+				return errors.ErrNotFound.WithDescription("employee not found").WithOperation("HardDeleteEmployee")
+			}
+			logrus.WithError(err).Error("Failed to delete employee")
+			return errors.Wrap(err, "DATABASE_ERROR", "failed to delete employee").WithOperation("HardDeleteEmployee")
+		}
+		return nil
+	})
 }
 
 // PatchEmployee updates specific fields of an employee
@@ -298,6 +689,10 @@ func (s *employeeService) PatchEmployee(ctx context.Context, uuid string, req *m
 		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to find employee").WithOperation("PatchEmployee")
 	}
 
+	if err := s.validator.ValidatePatch(ctx, req, existing); err != nil {
+		return nil, err
+	}
+
 	// Update fields if they are provided in the request
 	if req.EmployeeStatus != nil {
 		existing.Status = *req.EmployeeStatus
@@ -305,8 +700,26 @@ func (s *employeeService) PatchEmployee(ctx context.Context, uuid string, req *m
 	if req.EmployeeType != nil {
 		existing.EmployeeType = *req.EmployeeType
 	}
-	if req.IsActive != nil {
-		existing.IsActive = *req.IsActive
+	if req.Phone != nil {
+		existing.MobileNumber = *req.Phone
+	}
+	if req.EmailId != nil {
+		existing.Email = *req.EmailId
+	}
+	if req.DateOfBirth != nil {
+		existing.DateOfBirth = req.DateOfBirth
+	}
+	if req.Gender != nil {
+		existing.Gender = *req.Gender
+	}
+	if req.PanNumber != nil {
+		existing.PanNumber = *req.PanNumber
+	}
+	if req.AadhaarNumber != nil {
+		existing.AadhaarNumber = *req.AadhaarNumber
+	}
+	if req.DateOfRetirement != nil {
+		existing.DateOfRetirement = req.DateOfRetirement
 	}
 
 	// Update the last_modified_time
@@ -320,43 +733,5 @@ func (s *employeeService) PatchEmployee(ctx context.Context, uuid string, req *m
 	}
 
 	// Return the updated employee
-	return toEmployeeResponse(existing, s.jurisdictionSvc, tenantID)
-}
-
-// DeactivateEmployee deactivates an employee
-func (s *employeeService) DeactivateEmployee(ctx context.Context, uuid string, req *models.DeactivationDetails, tenantID string) (*models.EmployeeResponse, error) {
-	// Log the deactivation details
-	logrus.WithFields(logrus.Fields{
-		"employee_id":    uuid,
-		"reason":         req.ReasonForDeactivation,
-		"effective_from": req.EffectiveFrom,
-		"remarks":        req.Remarks,
-	}).Info("Deactivating employee")
-
-	// Update status to inactive
-	if err := s.repo.UpdateIsActive(ctx, uuid, false, tenantID); err != nil {
-		logrus.WithError(err).Error("Failed to deactivate employee")
-		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to deactivate employee").WithOperation("DeactivateEmployee")
-	}
-
-	return s.GetEmployeeByUUID(ctx, uuid, tenantID)
-}
-
-// ReactivateEmployee reactivates an employee
-func (s *employeeService) ReactivateEmployee(ctx context.Context, uuid string, req *models.ReactivationDetails, tenantID string) (*models.EmployeeResponse, error) {
-	// Log the reactivation details
-	logrus.WithFields(logrus.Fields{
-		"employee_id":             uuid,
-		"reason_for_reactivation": req.ReasonForReactivation,
-		"effective_from":          req.EffectiveFrom,
-		"remarks":                 req.Remarks,
-	}).Info("Reactivating employee")
-
-	// Update status to active
-	if err := s.repo.UpdateIsActive(ctx, uuid, true, tenantID); err != nil {
-		logrus.WithError(err).Error("Failed to reactivate employee")
-		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to reactivate employee").WithOperation("ReactivateEmployee")
-	}
-
-	return s.GetEmployeeByUUID(ctx, uuid, tenantID)
+	return s.toEmployeeResponse(ctx, existing, tenantID)
 }
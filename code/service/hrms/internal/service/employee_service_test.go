@@ -0,0 +1,399 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"hrms/internal/config"
+	"hrms/internal/models"
+	"hrms/internal/validator"
+	"hrms/pkg/errors"
+)
+
+type fakeAssignmentService struct {
+	AssignmentService
+	assignments []*models.Assignment
+}
+
+func (s *fakeAssignmentService) GetAssignmentsByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.Assignment, error) {
+	return s.assignments, nil
+}
+
+func (s *fakeAssignmentService) ValidateAssignments(assignments []*models.Assignment) error {
+	return nil
+}
+
+// ReplaceAssignments merges like the real service: assignments without an ID are added
+// and none are removed
+func (s *fakeAssignmentService) ReplaceAssignments(ctx context.Context, employeeID string, assignments []*models.Assignment, tenantID string) ([]*models.Assignment, error) {
+	for _, a := range assignments {
+		if a.ID == "" {
+			s.assignments = append(s.assignments, a)
+		}
+	}
+	return s.assignments, nil
+}
+
+type fakeServiceHistoryService struct {
+	ServiceHistoryService
+	records []*models.ServiceHistory
+}
+
+func (s *fakeServiceHistoryService) ValidateServiceHistory(records []*models.ServiceHistory) error {
+	return nil
+}
+
+func (s *fakeServiceHistoryService) ReplaceServiceHistory(ctx context.Context, employeeID string, records []*models.ServiceHistory, tenantID string) ([]*models.ServiceHistory, error) {
+	s.records = records
+	return records, nil
+}
+
+func (s *fakeServiceHistoryService) GetServiceHistoryByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.ServiceHistory, error) {
+	return s.records, nil
+}
+
+// fakeJurisdictionService holds the jurisdictions of a single employee
+type fakeJurisdictionService struct {
+	JurisdictionService
+	jurisdictions []*models.JurisdictionResponse
+}
+
+func (s *fakeJurisdictionService) SearchJurisdictions(ctx context.Context, criteria *models.JurisdictionSearchCriteria) ([]*models.JurisdictionResponse, error) {
+	return s.jurisdictions, nil
+}
+
+func (s *fakeJurisdictionService) DeleteJurisdiction(ctx context.Context, id, tenantID string) error {
+	for i, j := range s.jurisdictions {
+		if j.ID == id {
+			s.jurisdictions = append(s.jurisdictions[:i], s.jurisdictions[i+1:]...)
+			return nil
+		}
+	}
+	return errors.ErrNotFound.WithDescription("jurisdiction not found")
+}
+
+func (s *fakeJurisdictionService) ReplaceEmployeeJurisdictions(ctx context.Context, employeeID string, reqs []*models.CreateJurisdictionRequest, tenantID string) ([]*models.JurisdictionResponse, error) {
+	s.jurisdictions = nil
+	for _, r := range reqs {
+		s.jurisdictions = append(s.jurisdictions, &models.JurisdictionResponse{EmployeeID: employeeID, Boundary: r.Boundary})
+	}
+	return s.jurisdictions, nil
+}
+
+func TestHardDeleteEmployee(t *testing.T) {
+	const id = "emp-1"
+
+	tests := []struct {
+		name          string
+		assignments   []*models.Assignment
+		history       []*models.StatusHistory
+		missing       bool
+		wantCode      string
+		wantRemaining bool
+	}{
+		{name: "employee without history", wantRemaining: false},
+		{
+			name:          "employee with assignments",
+			assignments:   []*models.Assignment{{ID: "a-1", EmployeeID: id}},
+			wantCode:      "EMPLOYEE_HAS_ASSIGNMENTS",
+			wantRemaining: true,
+		},
+		{
+			name:          "deactivated employee",
+			history:       []*models.StatusHistory{{ID: "h-1", EmployeeID: id, Action: models.StatusActionDeactivation}},
+			wantCode:      "EMPLOYEE_HAS_STATUS_HISTORY",
+			wantRemaining: true,
+		},
+		{name: "unknown employee", missing: true, wantCode: "NOT_FOUND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &statusStore{
+				employees: map[string]*models.Employee{},
+				actions:   map[string]*models.PendingStatusAction{},
+				history:   tt.history,
+			}
+			if !tt.missing {
+				store.employees[id] = &models.Employee{ID: id, TenantID: testTenant}
+			}
+			jurisdictions := &fakeJurisdictionService{jurisdictions: []*models.JurisdictionResponse{{ID: "j-1", EmployeeID: id}}}
+			s := newStatusService(store, nil)
+			s.assignmentSvc = &fakeAssignmentService{assignments: tt.assignments}
+			s.jurisdictionSvc = jurisdictions
+
+			err := s.HardDeleteEmployee(context.Background(), id, testTenant)
+			if code := errorCode(err); code != tt.wantCode {
+				t.Fatalf("error code = %q, want %q (err: %v)", code, tt.wantCode, err)
+			}
+			if _, ok := store.employees[id]; ok != tt.wantRemaining {
+				t.Fatalf("employee remaining = %v, want %v", ok, tt.wantRemaining)
+			}
+			if len(store.history) != len(tt.history) {
+				t.Fatalf("status history has %d entries, want %d", len(store.history), len(tt.history))
+			}
+			if tt.wantRemaining && len(jurisdictions.jurisdictions) != 1 {
+				t.Fatalf("jurisdictions of a kept employee were deleted")
+			}
+		})
+	}
+}
+
+func TestUpdateEmployeeChildLists(t *testing.T) {
+	const id = "emp-1"
+	const base = `"employeeType": "PERMANENT", "department": "ENG", "designation": "AE"`
+
+	// want holds the number of assignments, service history records, education entries,
+	// tests and jurisdictions stored after the update; each starts with one
+	tests := []struct {
+		name string
+		body string
+		want [5]int
+	}{
+		{name: "lists omitted are kept", body: `{` + base + `}`, want: [5]int{1, 1, 1, 1, 1}},
+		{
+			name: "null lists are kept",
+			body: `{` + base + `, "assignments": null, "serviceHistory": null, "education": null, "tests": null, "jurisdictions": null}`,
+			want: [5]int{1, 1, 1, 1, 1},
+		},
+		{
+			name: "empty lists are cleared, assignments are never removed",
+			body: `{` + base + `, "assignments": [], "serviceHistory": [], "education": [], "tests": [], "jurisdictions": []}`,
+			want: [5]int{1, 0, 0, 0, 0},
+		},
+		{
+			name: "education cleared, tests kept",
+			body: `{` + base + `, "education": []}`,
+			want: [5]int{1, 1, 0, 1, 1},
+		},
+		{
+			name: "tests replaced, education kept",
+			body: `{` + base + `, "tests": [{"test": "Accounts", "yearOfPassing": 2015}, {"test": "Language", "yearOfPassing": 2016}]}`,
+			want: [5]int{1, 1, 1, 2, 1},
+		},
+		{
+			name: "given lists are replaced",
+			body: `{` + base + `, "serviceHistory": [{"serviceStatus": "A"}, {"serviceStatus": "B"}], "jurisdictions": [{"boundary": "WARD-1"}, {"boundary": "WARD-2"}]}`,
+			want: [5]int{1, 2, 1, 1, 2},
+		},
+		{
+			name: "assignments are merged",
+			body: `{` + base + `, "assignments": [{"department": "ENG", "designation": "AE", "fromDate": "2024-01-01T00:00:00Z"}]}`,
+			want: [5]int{2, 1, 1, 1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req models.CreateEmployeeRequest
+			if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+				t.Fatalf("decode request: %v", err)
+			}
+
+			store := &statusStore{
+				employees: map[string]*models.Employee{id: {ID: id, TenantID: testTenant, EmployeeType: "PERMANENT", Department: "ENG", Designation: "AE"}},
+				actions:   map[string]*models.PendingStatusAction{},
+			}
+			assignments := &fakeAssignmentService{assignments: []*models.Assignment{{ID: "a-1", EmployeeID: id}}}
+			history := &fakeServiceHistoryService{records: []*models.ServiceHistory{{ID: "sh-1", EmployeeID: id}}}
+			qualifications := &fakeQualificationRepo{
+				education: []*models.EducationalDetail{{ID: "ed-1", EmployeeID: id, Degree: "BE", YearOfPassing: 2010}},
+				tests:     []*models.DepartmentalTest{{ID: "dt-1", EmployeeID: id, Test: "Accounts", YearOfPassing: 2012}},
+			}
+			jurisdictions := &fakeJurisdictionService{jurisdictions: []*models.JurisdictionResponse{{ID: "j-1", EmployeeID: id}}}
+
+			s := newStatusService(store, nil)
+			s.validator = validator.NewEmployeeValidator(&fakeEmployeeRepo{store: store}, &config.Config{})
+			s.assignmentSvc = assignments
+			s.historySvc = history
+			s.qualificationSvc = NewQualificationService(qualifications)
+			s.jurisdictionSvc = jurisdictions
+
+			resp, err := s.UpdateEmployee(context.Background(), id, &req, testTenant)
+			if err != nil {
+				t.Fatalf("UpdateEmployee: %v", err)
+			}
+
+			got := [5]int{len(assignments.assignments), len(history.records), len(qualifications.education), len(qualifications.tests), len(jurisdictions.jurisdictions)}
+			if got != tt.want {
+				t.Fatalf("stored assignments, service history, education, tests, jurisdictions = %v, want %v", got, tt.want)
+			}
+			if len(resp.ServiceHistory) != tt.want[1] || len(resp.Education) != tt.want[2] || len(resp.Tests) != tt.want[3] {
+				t.Fatalf("response does not reflect the stored lists: %d service history, %d education, %d tests",
+					len(resp.ServiceHistory), len(resp.Education), len(resp.Tests))
+			}
+		})
+	}
+}
+
+func TestUpdateEmployeeIdentifiers(t *testing.T) {
+	const id = "emp-1"
+	const base = `"employeeType": "PERMANENT", "department": "ENG", "designation": "AE"`
+
+	tests := []struct {
+		name           string
+		stored         models.Employee
+		body           string
+		wantCode       string
+		wantUserID     string
+		wantIndividual string
+		wantFields     []string
+	}{
+		{
+			name:           "identifiers left out are kept",
+			stored:         models.Employee{Code: "EMP-1", UserID: "user-1", IndividualID: "ind-1"},
+			body:           `{` + base + `}`,
+			wantCode:       "EMP-1",
+			wantUserID:     "user-1",
+			wantIndividual: "ind-1",
+		},
+		{
+			name:           "same identifiers are accepted",
+			stored:         models.Employee{Code: "EMP-1", UserID: "user-1", IndividualID: "ind-1"},
+			body:           `{` + base + `, "code": "EMP-1", "userId": "user-1", "individualId": "ind-1"}`,
+			wantCode:       "EMP-1",
+			wantUserID:     "user-1",
+			wantIndividual: "ind-1",
+		},
+		{
+			name:           "individual can be relinked",
+			stored:         models.Employee{Code: "EMP-1", UserID: "user-1", IndividualID: "ind-1"},
+			body:           `{` + base + `, "individualId": "ind-2"}`,
+			wantCode:       "EMP-1",
+			wantUserID:     "user-1",
+			wantIndividual: "ind-2",
+		},
+		{
+			name:       "code and user cannot be changed",
+			stored:     models.Employee{Code: "EMP-1", UserID: "user-1"},
+			body:       `{` + base + `, "code": "EMP-2", "userId": "user-2"}`,
+			wantFields: []string{"code", "userId"},
+		},
+		{
+			name:       "missing code and user can be set",
+			body:       `{` + base + `, "code": "EMP-2", "userId": "user-2"}`,
+			wantCode:   "EMP-2",
+			wantUserID: "user-2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req models.CreateEmployeeRequest
+			if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+				t.Fatalf("decode request: %v", err)
+			}
+
+			stored := tt.stored
+			stored.ID, stored.TenantID = id, testTenant
+			stored.EmployeeType, stored.Department, stored.Designation = "PERMANENT", "ENG", "AE"
+			store := &statusStore{
+				employees: map[string]*models.Employee{id: &stored},
+				actions:   map[string]*models.PendingStatusAction{},
+			}
+			s := newStatusService(store, nil)
+			s.validator = validator.NewEmployeeValidator(&fakeEmployeeRepo{store: store}, &config.Config{})
+			s.assignmentSvc = &fakeAssignmentService{}
+			s.historySvc = &fakeServiceHistoryService{}
+			s.qualificationSvc = NewQualificationService(&fakeQualificationRepo{})
+			s.jurisdictionSvc = &fakeJurisdictionService{}
+
+			_, err := s.UpdateEmployee(context.Background(), id, &req, testTenant)
+			if len(tt.wantFields) > 0 {
+				var fields []string
+				var multi *errors.MultiError
+				if !stderrors.As(err, &multi) {
+					t.Fatalf("error = %v, want field errors on %v", err, tt.wantFields)
+				}
+				for _, e := range multi.Errors {
+					fields = append(fields, fieldOf(e))
+				}
+				if fmt.Sprint(fields) != fmt.Sprint(tt.wantFields) {
+					t.Fatalf("field errors on %v, want %v", fields, tt.wantFields)
+				}
+				if got := store.employees[id]; got.Code != tt.stored.Code || got.UserID != tt.stored.UserID {
+					t.Fatalf("rejected update changed the employee to code %q, user %q", got.Code, got.UserID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateEmployee: %v", err)
+			}
+
+			got := store.employees[id]
+			if got.Code != tt.wantCode || got.UserID != tt.wantUserID || got.IndividualID != tt.wantIndividual {
+				t.Fatalf("stored code %q, user %q, individual %q; want %q, %q, %q",
+					got.Code, got.UserID, got.IndividualID, tt.wantCode, tt.wantUserID, tt.wantIndividual)
+			}
+		})
+	}
+}
+
+func TestIsActiveOnUpdate(t *testing.T) {
+	const id = "emp-1"
+	const base = `"employeeType": "PERMANENT", "department": "ENG", "designation": "AE"`
+	active, inactive := true, false
+
+	tests := []struct {
+		name      string
+		stored    bool
+		put       string
+		patch     *models.UpdateEmployeeRequest
+		wantError bool
+	}{
+		{name: "put without isActive", stored: true, put: `{` + base + `}`},
+		{name: "put with the stored isActive", stored: true, put: `{` + base + `, "isActive": true}`},
+		{name: "put deactivating", stored: true, put: `{` + base + `, "isActive": false}`, wantError: true},
+		{name: "put reactivating", stored: false, put: `{` + base + `, "isActive": true}`, wantError: true},
+		{name: "patch without isActive", stored: true, patch: &models.UpdateEmployeeRequest{}},
+		{name: "patch with the stored isActive", stored: false, patch: &models.UpdateEmployeeRequest{IsActive: &inactive}},
+		{name: "patch deactivating", stored: true, patch: &models.UpdateEmployeeRequest{IsActive: &inactive}, wantError: true},
+		{name: "patch reactivating", stored: false, patch: &models.UpdateEmployeeRequest{IsActive: &active}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &statusStore{
+				employees: map[string]*models.Employee{id: {ID: id, TenantID: testTenant, EmployeeType: "PERMANENT", Department: "ENG", Designation: "AE", IsActive: tt.stored}},
+				actions:   map[string]*models.PendingStatusAction{},
+			}
+			s := newStatusService(store, nil)
+			s.validator = validator.NewEmployeeValidator(&fakeEmployeeRepo{store: store}, &config.Config{})
+			s.assignmentSvc = &fakeAssignmentService{}
+			s.historySvc = &fakeServiceHistoryService{}
+			s.qualificationSvc = NewQualificationService(&fakeQualificationRepo{})
+			s.jurisdictionSvc = &fakeJurisdictionService{}
+
+			var err error
+			if tt.patch != nil {
+				_, err = s.PatchEmployee(context.Background(), id, tt.patch, testTenant)
+			} else {
+				var req models.CreateEmployeeRequest
+				if err := json.Unmarshal([]byte(tt.put), &req); err != nil {
+					t.Fatalf("decode request: %v", err)
+				}
+				_, err = s.UpdateEmployee(context.Background(), id, &req, testTenant)
+			}
+
+			if !tt.wantError {
+				if err != nil {
+					t.Fatalf("update: %v", err)
+				}
+			} else {
+				var multi *errors.MultiError
+				if !stderrors.As(err, &multi) || len(multi.Errors) != 1 || fieldOf(multi.Errors[0]) != "isActive" {
+					t.Fatalf("error = %v, want a single field error on isActive", err)
+				}
+			}
+			if got := store.employees[id].IsActive; got != tt.stored {
+				t.Fatalf("isActive = %v, want it to stay %v", got, tt.stored)
+			}
+			if len(store.history) != 0 {
+				t.Fatalf("update wrote %d status history entries", len(store.history))
+			}
+		})
+	}
+}
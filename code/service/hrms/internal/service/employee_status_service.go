@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"hrms/internal/models"
+	"hrms/internal/requestctx"
+	"hrms/pkg/errors"
+)
+
+// dueActionBatchSize caps how many scheduled status changes are applied per run
+const dueActionBatchSize = 100
+
+// DeactivateEmployee deactivates an employee and records the deactivation details.
+// A future effectiveFrom schedules the deactivation instead of applying it immediately.
+func (s *employeeService) DeactivateEmployee(ctx context.Context, uuid string, req *models.DeactivationDetails, tenantID string) (*models.EmployeeResponse, error) {
+	change := &models.PendingStatusAction{
+		EmployeeID:    uuid,
+		Action:        models.StatusActionDeactivation,
+		Reason:        req.ReasonForDeactivation,
+		EffectiveFrom: req.EffectiveFrom,
+		Remarks:       req.Remarks,
+		TenantID:      tenantID,
+		CreatedBy:     requestctx.ActorID(ctx),
+	}
+	if err := s.changeStatus(ctx, change, "DeactivateEmployee"); err != nil {
+		return nil, err
+	}
+
+	return s.GetEmployeeByUUID(ctx, uuid, tenantID)
+}
+
+// ReactivateEmployee reactivates an employee and records the reactivation details.
+// A future effectiveFrom schedules the reactivation instead of applying it immediately.
+func (s *employeeService) ReactivateEmployee(ctx context.Context, uuid string, req *models.ReactivationDetails, tenantID string) (*models.EmployeeResponse, error) {
+	change := &models.PendingStatusAction{
+		EmployeeID:    uuid,
+		Action:        models.StatusActionReactivation,
+		Reason:        req.ReasonForReactivation,
+		EffectiveFrom: req.EffectiveFrom,
+		Remarks:       req.Remarks,
+		TenantID:      tenantID,
+		CreatedBy:     requestctx.ActorID(ctx),
+	}
+	if err := s.changeStatus(ctx, change, "ReactivateEmployee"); err != nil {
+		return nil, err
+	}
+
+	return s.GetEmployeeByUUID(ctx, uuid, tenantID)
+}
+
+// GetStatusHistory retrieves the deactivation and reactivation history of an employee
+func (s *employeeService) GetStatusHistory(ctx context.Context, uuid, tenantID string) ([]*models.StatusHistory, error) {
+	if _, err := s.findEmployee(ctx, uuid, tenantID, "GetStatusHistory"); err != nil {
+		return nil, err
+	}
+
+	history, err := s.deactivationRepo.FindByEmployeeID(ctx, uuid, tenantID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get status history")
+		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to get status history").WithOperation("GetStatusHistory")
+	}
+
+	return history, nil
+}
+
+// ListPendingActions lists the scheduled status changes of an employee, optionally filtered by status
+func (s *employeeService) ListPendingActions(ctx context.Context, uuid, status, tenantID string) ([]*models.PendingStatusAction, error) {
+	if _, err := s.findEmployee(ctx, uuid, tenantID, "ListPendingActions"); err != nil {
+		return nil, err
+	}
+
+	actions, err := s.pendingActionRepo.FindByEmployeeID(ctx, uuid, status, tenantID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list pending actions")
+		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to list pending actions").WithOperation("ListPendingActions")
+	}
+
+	return actions, nil
+}
+
+// CancelPendingAction cancels a scheduled status change that has not been applied yet
+func (s *employeeService) CancelPendingAction(ctx context.Context, uuid, actionID, tenantID string) (*models.PendingStatusAction, error) {
+	action, err := s.pendingActionRepo.FindByUUID(ctx, actionID, tenantID)
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return nil, errors.ErrNotFound.WithDescription("pending action not found").WithOperation("CancelPendingAction")
+		}
+		logrus.WithError(err).Error("Failed to find pending action")
+		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to find pending action").WithOperation("CancelPendingAction")
+	}
+	if action.EmployeeID != uuid {
+		return nil, errors.ErrNotFound.WithDescription("pending action not found").WithOperation("CancelPendingAction")
+	}
+
+	cancelled, err := s.pendingActionRepo.TransitionStatus(ctx, actionID, models.PendingActionStatusPending, models.PendingActionStatusCancelled, "")
+	if err != nil {
+		logrus.WithError(err).Error("Failed to cancel pending action")
+		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to cancel pending action").WithOperation("CancelPendingAction")
+	}
+	if !cancelled {
+		return nil, errors.New("PENDING_ACTION_NOT_CANCELLABLE", "action is no longer pending").WithOperation("CancelPendingAction")
+	}
+
+	return s.pendingActionRepo.FindByUUID(ctx, actionID, tenantID)
+}
+
+// ApplyDueStatusActions applies every scheduled status change whose effective time is at or before now.
+// Each action is locked, applied and marked APPLIED in one transaction, so concurrent instances never
+// apply the same action twice and an action whose transaction fails stays PENDING to be retried on the
+// next run. Actions that no longer fit the employee's state are marked FAILED instead.
+func (s *employeeService) ApplyDueStatusActions(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.pendingActionRepo.FindDue(ctx, now, dueActionBatchSize)
+	if err != nil {
+		return 0, errors.Wrap(err, "DATABASE_ERROR", "failed to find due pending actions").WithOperation("ApplyDueStatusActions")
+	}
+
+	applied := 0
+	for _, action := range due {
+		ok, err := s.applyDueAction(ctx, action.ID)
+		if err != nil {
+			logrus.WithError(err).WithField("action_id", action.ID).Warn("Failed to apply scheduled status change; it stays pending")
+			continue
+		}
+		if ok {
+			applied++
+		}
+	}
+
+	return applied, nil
+}
+
+// changeStatus applies a status change now, or stores it as a pending action when it is future-dated
+func (s *employeeService) changeStatus(ctx context.Context, change *models.PendingStatusAction, op string) error {
+	existing, err := s.findEmployee(ctx, change.EmployeeID, change.TenantID, op)
+	if err != nil {
+		return err
+	}
+	if err := checkStatusTransition(existing, change.Action, op); err != nil {
+		return err
+	}
+
+	pending, err := s.pendingActionRepo.FindByEmployeeID(ctx, change.EmployeeID, models.PendingActionStatusPending, change.TenantID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to check pending actions")
+		return errors.Wrap(err, "DATABASE_ERROR", "failed to check pending actions").WithOperation(op)
+	}
+	if len(pending) > 0 {
+		return errors.New("PENDING_ACTION_EXISTS", "employee already has a scheduled status change").
+			WithDescription("cancel the pending action before requesting another status change").
+			WithOperation(op)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"employee_id":    change.EmployeeID,
+		"action":         change.Action,
+		"reason":         change.Reason,
+		"effective_from": change.EffectiveFrom,
+		"remarks":        change.Remarks,
+	}).Info("Changing employee status")
+
+	if change.EffectiveFrom != nil && change.EffectiveFrom.After(time.Now()) {
+		change.Status = models.PendingActionStatusPending
+		if err := s.pendingActionRepo.Create(ctx, change); err != nil {
+			logrus.WithError(err).Error("Failed to schedule status change")
+			return errors.Wrap(err, "DATABASE_ERROR", "failed to schedule status change").WithOperation(op)
+		}
+		return nil
+	}
+
+	return s.applyStatusChange(ctx, existing, change, op)
+}
+
+// applyDueAction claims a due action, re-checks it against the employee's current state and
+// applies it in one transaction. It reports whether the action was applied; an action that
+// another instance holds or that no longer fits is not.
+func (s *employeeService) applyDueAction(ctx context.Context, id string) (bool, error) {
+	const op = "ApplyDueStatusActions"
+
+	applied := false
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		action, err := s.pendingActionRepo.ClaimPending(ctx, id)
+		if err != nil {
+			return err
+		}
+		if action == nil {
+			return nil
+		}
+
+		existing, err := s.findEmployee(ctx, action.EmployeeID, action.TenantID, op)
+		if errors.Is(err, errors.ErrNotFound) {
+			return s.failDueAction(ctx, id, err)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkStatusTransition(existing, action.Action, op); err != nil {
+			return s.failDueAction(ctx, id, err)
+		}
+
+		// Flipped before the status change, whose account update must come last
+		if _, err := s.pendingActionRepo.TransitionStatus(ctx, id, models.PendingActionStatusPending, models.PendingActionStatusApplied, ""); err != nil {
+			return err
+		}
+		if err := s.applyStatusChange(ctx, existing, action, op); err != nil {
+			return err
+		}
+		applied = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return applied, nil
+}
+
+// failDueAction marks a claimed action that can never apply as FAILED
+func (s *employeeService) failDueAction(ctx context.Context, id string, reason error) error {
+	logrus.WithError(reason).WithField("action_id", id).Warn("Scheduled status change no longer applies")
+	_, err := s.pendingActionRepo.TransitionStatus(ctx, id, models.PendingActionStatusPending, models.PendingActionStatusFailed, reason.Error())
+	return err
+}
+
+// applyStatusChange flips is_active and appends the change to the status history in one
+// transaction, and enables or disables the employee's login account to match. The account
+// change is reverted if the transaction fails.
+func (s *employeeService) applyStatusChange(ctx context.Context, employee *models.Employee, change *models.PendingStatusAction, op string) error {
+	isActive := change.Action == models.StatusActionReactivation
+
+	var undo compensations
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateIsActive(ctx, change.EmployeeID, isActive, change.TenantID); err != nil {
+			logrus.WithError(err).Error("Failed to update employee status")
+			return errors.Wrap(err, "DATABASE_ERROR", "failed to update employee status").WithOperation(op)
+		}
+
+		record := &models.StatusHistory{
+			EmployeeID:    change.EmployeeID,
+			Action:        change.Action,
+			Reason:        change.Reason,
+			EffectiveFrom: change.EffectiveFrom,
+			Remarks:       change.Remarks,
+			TenantID:      change.TenantID,
+			CreatedBy:     change.CreatedBy,
+		}
+		if err := s.deactivationRepo.Create(ctx, record); err != nil {
+			logrus.WithError(err).Error("Failed to record status change")
+			return errors.Wrap(err, "DATABASE_ERROR", "failed to record status change").WithOperation(op)
+		}
+
+		// Called last so only the commit can fail after the account has changed
+		return s.setUserActive(ctx, employee, isActive, &undo, op)
+	})
+	if err != nil {
+		undo.run(ctx)
+	}
+	return err
+}
+
+// findEmployee loads an employee and maps repository errors for the given operation
+func (s *employeeService) findEmployee(ctx context.Context, uuid, tenantID, op string) (*models.Employee, error) {
+	employee, err := s.repo.FindByUUID(ctx, uuid, tenantID)
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return nil, errors.ErrNotFound.WithDescription("employee not found").WithOperation(op)
+		}
+		logrus.WithError(err).Error("Failed to find employee")
+		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to find employee").WithOperation(op)
+	}
+	return employee, nil
+}
+
+// checkStatusTransition rejects deactivating an inactive employee or reactivating an active one
+func checkStatusTransition(employee *models.Employee, action, op string) error {
+	if action == models.StatusActionDeactivation && !employee.IsActive {
+		return errors.New("EMPLOYEE_DEACTIVATED", "employee is already inactive").WithOperation(op)
+	}
+	if action == models.StatusActionReactivation && employee.IsActive {
+		return errors.New("EMPLOYEE_ACTIVE", "employee is already active").WithOperation(op)
+	}
+	return nil
+}
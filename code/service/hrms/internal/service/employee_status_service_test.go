@@ -0,0 +1,507 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"hrms/internal/clients/user"
+	"hrms/internal/models"
+	"hrms/internal/repository"
+	"hrms/pkg/errors"
+)
+
+// statusStore is the in-memory state behind the status fakes. fakeUnitOfWork restores it
+// when a transaction fails, so tests can check what a rollback leaves behind.
+type statusStore struct {
+	employees map[string]*models.Employee
+	actions   map[string]*models.PendingStatusAction
+	history   []*models.StatusHistory
+
+	failUpdateIsActive bool
+	failHistory        bool
+}
+
+func (s *statusStore) snapshot() *statusStore {
+	c := *s
+	c.employees = make(map[string]*models.Employee, len(s.employees))
+	for id, e := range s.employees {
+		copied := *e
+		c.employees[id] = &copied
+	}
+	c.actions = make(map[string]*models.PendingStatusAction, len(s.actions))
+	for id, a := range s.actions {
+		copied := *a
+		c.actions[id] = &copied
+	}
+	c.history = append([]*models.StatusHistory(nil), s.history...)
+	return &c
+}
+
+type fakeTxKey struct{}
+
+type fakeUnitOfWork struct{ store *statusStore }
+
+func (u *fakeUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(fakeTxKey{}) != nil {
+		return fn(ctx)
+	}
+	saved := u.store.snapshot()
+	if err := fn(context.WithValue(ctx, fakeTxKey{}, true)); err != nil {
+		*u.store = *saved
+		return err
+	}
+	return nil
+}
+
+type fakeEmployeeRepo struct {
+	repository.EmployeeRepository
+	store *statusStore
+}
+
+func (r *fakeEmployeeRepo) FindByUUID(ctx context.Context, uuid, tenantID string) (*models.Employee, error) {
+	e, ok := r.store.employees[uuid]
+	if !ok || e.TenantID != tenantID {
+		return nil, errors.ErrNotFound.WithDescription("employee not found")
+	}
+	copied := *e
+	return &copied, nil
+}
+
+func (r *fakeEmployeeRepo) FindByCode(ctx context.Context, code, tenantID string) (*models.Employee, error) {
+	for _, e := range r.store.employees {
+		if e.Code == code && e.TenantID == tenantID {
+			copied := *e
+			return &copied, nil
+		}
+	}
+	return nil, errors.ErrNotFound.WithDescription("employee not found")
+}
+
+func (r *fakeEmployeeRepo) UpdateIsActive(ctx context.Context, id string, isActive bool, tenantID string) error {
+	if r.store.failUpdateIsActive {
+		return stderrors.New("connection reset")
+	}
+	r.store.employees[id].IsActive = isActive
+	return nil
+}
+
+func (r *fakeEmployeeRepo) Delete(ctx context.Context, id, tenantID string) error {
+	e, ok := r.store.employees[id]
+	if !ok || e.TenantID != tenantID {
+		return errors.ErrNotFound.WithDescription("employee not found")
+	}
+	delete(r.store.employees, id)
+	return nil
+}
+
+func (r *fakeEmployeeRepo) Update(ctx context.Context, employee *models.Employee) error {
+	copied := *employee
+	r.store.employees[employee.ID] = &copied
+	return nil
+}
+
+type fakeDeactivationRepo struct{ store *statusStore }
+
+func (r *fakeDeactivationRepo) Create(ctx context.Context, record *models.StatusHistory) error {
+	if r.store.failHistory {
+		return stderrors.New("connection reset")
+	}
+	r.store.history = append(r.store.history, record)
+	return nil
+}
+
+func (r *fakeDeactivationRepo) FindByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.StatusHistory, error) {
+	return r.store.history, nil
+}
+
+type fakePendingActionRepo struct{ store *statusStore }
+
+func (r *fakePendingActionRepo) Create(ctx context.Context, action *models.PendingStatusAction) error {
+	if action.ID == "" {
+		action.ID = "action-new"
+	}
+	r.store.actions[action.ID] = action
+	return nil
+}
+
+func (r *fakePendingActionRepo) FindByUUID(ctx context.Context, uuid, tenantID string) (*models.PendingStatusAction, error) {
+	a, ok := r.store.actions[uuid]
+	if !ok || a.TenantID != tenantID {
+		return nil, errors.ErrNotFound.WithDescription("pending action not found")
+	}
+	return a, nil
+}
+
+func (r *fakePendingActionRepo) FindByEmployeeID(ctx context.Context, employeeID, status, tenantID string) ([]*models.PendingStatusAction, error) {
+	var found []*models.PendingStatusAction
+	for _, a := range r.store.actions {
+		if a.EmployeeID == employeeID && (status == "" || a.Status == status) {
+			found = append(found, a)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakePendingActionRepo) FindDue(ctx context.Context, now time.Time, limit int) ([]*models.PendingStatusAction, error) {
+	var due []*models.PendingStatusAction
+	for _, a := range r.store.actions {
+		if a.Status == models.PendingActionStatusPending && !a.EffectiveFrom.After(now) {
+			due = append(due, a)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakePendingActionRepo) ClaimPending(ctx context.Context, id string) (*models.PendingStatusAction, error) {
+	a, ok := r.store.actions[id]
+	if !ok || a.Status != models.PendingActionStatusPending {
+		return nil, nil
+	}
+	copied := *a
+	return &copied, nil
+}
+
+func (r *fakePendingActionRepo) TransitionStatus(ctx context.Context, id, from, to, failureReason string) (bool, error) {
+	a, ok := r.store.actions[id]
+	if !ok || a.Status != from {
+		return false, nil
+	}
+	a.Status = to
+	a.FailureReason = failureReason
+	return true, nil
+}
+
+// fakeUserClient records account status changes and can be made to fail
+type fakeUserClient struct {
+	user.Client
+	active map[string]bool
+	fail   bool
+}
+
+func (c *fakeUserClient) SetActive(ctx context.Context, tenantID, userID string, active bool) error {
+	if c.fail {
+		return stderrors.New("user service unavailable")
+	}
+	c.active[userID] = active
+	return nil
+}
+
+func newStatusService(store *statusStore, users *fakeUserClient) *employeeService {
+	s := &employeeService{
+		repo:              &fakeEmployeeRepo{store: store},
+		deactivationRepo:  &fakeDeactivationRepo{store: store},
+		pendingActionRepo: &fakePendingActionRepo{store: store},
+		uow:               &fakeUnitOfWork{store: store},
+	}
+	if users != nil {
+		s.userClient = users
+	}
+	return s
+}
+
+func errorCode(err error) string {
+	if e, ok := err.(*errors.Error); ok {
+		return e.Code
+	}
+	if err != nil {
+		return "UNKNOWN"
+	}
+	return ""
+}
+
+func TestCheckStatusTransition(t *testing.T) {
+	tests := []struct {
+		name     string
+		isActive bool
+		action   string
+		wantCode string
+	}{
+		{name: "deactivate active employee", isActive: true, action: models.StatusActionDeactivation},
+		{name: "reactivate inactive employee", isActive: false, action: models.StatusActionReactivation},
+		{name: "deactivate inactive employee", isActive: false, action: models.StatusActionDeactivation, wantCode: "EMPLOYEE_DEACTIVATED"},
+		{name: "reactivate active employee", isActive: true, action: models.StatusActionReactivation, wantCode: "EMPLOYEE_ACTIVE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkStatusTransition(&models.Employee{IsActive: tt.isActive}, tt.action, "Test")
+			if got := errorCode(err); got != tt.wantCode {
+				t.Fatalf("code = %q, want %q (err: %v)", got, tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestChangeStatus(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		name          string
+		isActive      bool
+		existing      *models.PendingStatusAction
+		action        string
+		effectiveFrom *time.Time
+		wantCode      string
+		wantActive    bool
+		wantHistory   int
+		wantScheduled bool
+	}{
+		{
+			name:        "immediate deactivation",
+			isActive:    true,
+			action:      models.StatusActionDeactivation,
+			wantActive:  false,
+			wantHistory: 1,
+		},
+		{
+			name:          "past effectiveFrom applies immediately",
+			isActive:      false,
+			action:        models.StatusActionReactivation,
+			effectiveFrom: &past,
+			wantActive:    true,
+			wantHistory:   1,
+		},
+		{
+			name:          "future effectiveFrom is scheduled",
+			isActive:      true,
+			action:        models.StatusActionDeactivation,
+			effectiveFrom: &future,
+			wantActive:    true,
+			wantScheduled: true,
+		},
+		{
+			name:       "invalid transition is rejected",
+			isActive:   false,
+			action:     models.StatusActionDeactivation,
+			wantCode:   "EMPLOYEE_DEACTIVATED",
+			wantActive: false,
+		},
+		{
+			name:     "second change while one is pending is rejected",
+			isActive: true,
+			existing: &models.PendingStatusAction{
+				ID: "action-1", EmployeeID: "emp-1", TenantID: testTenant,
+				Action: models.StatusActionDeactivation, Status: models.PendingActionStatusPending, EffectiveFrom: &future,
+			},
+			action:     models.StatusActionDeactivation,
+			wantCode:   "PENDING_ACTION_EXISTS",
+			wantActive: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &statusStore{
+				employees: map[string]*models.Employee{"emp-1": {ID: "emp-1", TenantID: testTenant, UserID: "user-1", IsActive: tt.isActive}},
+				actions:   map[string]*models.PendingStatusAction{},
+			}
+			if tt.existing != nil {
+				store.actions[tt.existing.ID] = tt.existing
+			}
+			users := &fakeUserClient{active: map[string]bool{}}
+			s := newStatusService(store, users)
+
+			err := s.changeStatus(context.Background(), &models.PendingStatusAction{
+				EmployeeID: "emp-1", TenantID: testTenant, Action: tt.action, EffectiveFrom: tt.effectiveFrom,
+			}, "Test")
+
+			if got := errorCode(err); got != tt.wantCode {
+				t.Fatalf("code = %q, want %q (err: %v)", got, tt.wantCode, err)
+			}
+			if got := store.employees["emp-1"].IsActive; got != tt.wantActive {
+				t.Fatalf("isActive = %v, want %v", got, tt.wantActive)
+			}
+			if len(store.history) != tt.wantHistory {
+				t.Fatalf("history entries = %d, want %d", len(store.history), tt.wantHistory)
+			}
+			if tt.wantHistory > 0 && users.active["user-1"] != tt.wantActive {
+				t.Fatalf("user account active = %v, want %v", users.active["user-1"], tt.wantActive)
+			}
+			scheduled := store.actions["action-new"]
+			if tt.wantScheduled != (scheduled != nil) {
+				t.Fatalf("scheduled action = %+v, want scheduled %v", scheduled, tt.wantScheduled)
+			}
+			if scheduled != nil && scheduled.Status != models.PendingActionStatusPending {
+				t.Fatalf("scheduled action status = %s, want PENDING", scheduled.Status)
+			}
+		})
+	}
+}
+
+func TestApplyDueStatusActions(t *testing.T) {
+	now := time.Now()
+	due := now.Add(-time.Minute)
+
+	tests := []struct {
+		name        string
+		isActive    bool
+		missing     bool // the employee no longer exists
+		action      string
+		setup       func(store *statusStore, users *fakeUserClient)
+		wantApplied int
+		wantStatus  string
+		wantActive  bool
+		wantHistory int
+	}{
+		{
+			name:        "due deactivation is applied",
+			isActive:    true,
+			action:      models.StatusActionDeactivation,
+			wantApplied: 1,
+			wantStatus:  models.PendingActionStatusApplied,
+			wantActive:  false,
+			wantHistory: 1,
+		},
+		{
+			name:        "action that no longer fits is marked failed",
+			isActive:    false,
+			action:      models.StatusActionDeactivation,
+			wantStatus:  models.PendingActionStatusFailed,
+			wantActive:  false,
+			wantHistory: 0,
+		},
+		{
+			name:       "action of a deleted employee is marked failed",
+			missing:    true,
+			action:     models.StatusActionDeactivation,
+			wantStatus: models.PendingActionStatusFailed,
+		},
+		{
+			name:     "database failure rolls back and stays pending",
+			isActive: true,
+			action:   models.StatusActionDeactivation,
+			setup: func(store *statusStore, _ *fakeUserClient) {
+				store.failHistory = true
+			},
+			wantStatus:  models.PendingActionStatusPending,
+			wantActive:  true,
+			wantHistory: 0,
+		},
+		{
+			name:     "employee update failure stays pending",
+			isActive: true,
+			action:   models.StatusActionDeactivation,
+			setup: func(store *statusStore, _ *fakeUserClient) {
+				store.failUpdateIsActive = true
+			},
+			wantStatus:  models.PendingActionStatusPending,
+			wantActive:  true,
+			wantHistory: 0,
+		},
+		{
+			name:     "user service failure rolls back and stays pending",
+			isActive: false,
+			action:   models.StatusActionReactivation,
+			setup: func(_ *statusStore, users *fakeUserClient) {
+				users.fail = true
+			},
+			wantStatus:  models.PendingActionStatusPending,
+			wantActive:  false,
+			wantHistory: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &statusStore{
+				employees: map[string]*models.Employee{},
+				actions: map[string]*models.PendingStatusAction{"action-1": {
+					ID: "action-1", EmployeeID: "emp-1", TenantID: testTenant, Action: tt.action,
+					EffectiveFrom: &due, Status: models.PendingActionStatusPending,
+				}},
+			}
+			if !tt.missing {
+				store.employees["emp-1"] = &models.Employee{ID: "emp-1", TenantID: testTenant, UserID: "user-1", IsActive: tt.isActive}
+			}
+			users := &fakeUserClient{active: map[string]bool{}}
+			if tt.setup != nil {
+				tt.setup(store, users)
+			}
+			s := newStatusService(store, users)
+
+			applied, err := s.ApplyDueStatusActions(context.Background(), now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if applied != tt.wantApplied {
+				t.Fatalf("applied = %d, want %d", applied, tt.wantApplied)
+			}
+			if got := store.actions["action-1"].Status; got != tt.wantStatus {
+				t.Fatalf("action status = %s, want %s", got, tt.wantStatus)
+			}
+			if !tt.missing && store.employees["emp-1"].IsActive != tt.wantActive {
+				t.Fatalf("isActive = %v, want %v", store.employees["emp-1"].IsActive, tt.wantActive)
+			}
+			if len(store.history) != tt.wantHistory {
+				t.Fatalf("history entries = %d, want %d", len(store.history), tt.wantHistory)
+			}
+		})
+	}
+}
+
+func TestApplyDueStatusActionsSkipsFutureAndClaimedActions(t *testing.T) {
+	now := time.Now()
+	future := now.Add(time.Hour)
+	due := now.Add(-time.Minute)
+
+	store := &statusStore{
+		employees: map[string]*models.Employee{"emp-1": {ID: "emp-1", TenantID: testTenant, IsActive: true}},
+		actions: map[string]*models.PendingStatusAction{
+			"future": {ID: "future", EmployeeID: "emp-1", TenantID: testTenant, Action: models.StatusActionDeactivation,
+				EffectiveFrom: &future, Status: models.PendingActionStatusPending},
+			"cancelled": {ID: "cancelled", EmployeeID: "emp-1", TenantID: testTenant, Action: models.StatusActionDeactivation,
+				EffectiveFrom: &due, Status: models.PendingActionStatusCancelled},
+		},
+	}
+	s := newStatusService(store, &fakeUserClient{active: map[string]bool{}})
+
+	applied, err := s.ApplyDueStatusActions(context.Background(), now)
+	if err != nil || applied != 0 {
+		t.Fatalf("ApplyDueStatusActions = %d, %v; want nothing applied", applied, err)
+	}
+	if store.actions["future"].Status != models.PendingActionStatusPending || !store.employees["emp-1"].IsActive {
+		t.Fatalf("future action or employee changed: %+v", store.actions["future"])
+	}
+}
+
+func TestCancelPendingAction(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		status     string
+		employeeID string
+		actionID   string
+		wantCode   string
+		wantStatus string
+	}{
+		{name: "pending action is cancelled", status: models.PendingActionStatusPending, employeeID: "emp-1", actionID: "action-1", wantStatus: models.PendingActionStatusCancelled},
+		{name: "applied action cannot be cancelled", status: models.PendingActionStatusApplied, employeeID: "emp-1", actionID: "action-1", wantCode: "PENDING_ACTION_NOT_CANCELLABLE", wantStatus: models.PendingActionStatusApplied},
+		{name: "action of another employee is not found", status: models.PendingActionStatusPending, employeeID: "emp-2", actionID: "action-1", wantCode: errors.ErrNotFound.Code, wantStatus: models.PendingActionStatusPending},
+		{name: "unknown action is not found", status: models.PendingActionStatusPending, employeeID: "emp-1", actionID: "action-9", wantCode: errors.ErrNotFound.Code, wantStatus: models.PendingActionStatusPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &statusStore{
+				employees: map[string]*models.Employee{},
+				actions: map[string]*models.PendingStatusAction{"action-1": {
+					ID: "action-1", EmployeeID: "emp-1", TenantID: testTenant, Action: models.StatusActionDeactivation,
+					EffectiveFrom: &future, Status: tt.status,
+				}},
+			}
+			s := newStatusService(store, nil)
+
+			_, err := s.CancelPendingAction(context.Background(), tt.employeeID, tt.actionID, testTenant)
+			if got := errorCode(err); got != tt.wantCode {
+				t.Fatalf("code = %q, want %q (err: %v)", got, tt.wantCode, err)
+			}
+			if got := store.actions["action-1"].Status; got != tt.wantStatus {
+				t.Fatalf("action status = %s, want %s", got, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"hrms/internal/clients/user"
+	"hrms/internal/models"
+	"hrms/pkg/errors"
+)
+
+// compensations collects undo steps for calls to other services made while an
+// HRMS transaction is open. They run in reverse order when the transaction fails.
+type compensations []func(ctx context.Context) error
+
+// add registers an undo step
+func (c *compensations) add(fn func(ctx context.Context) error) {
+	*c = append(*c, fn)
+}
+
+// run executes the undo steps, most recent first. Failures are logged and do not stop
+// the remaining steps. The steps run even if the request context has been cancelled.
+func (c *compensations) run(ctx context.Context) {
+	ctx = context.WithoutCancel(ctx)
+	for i := len(*c) - 1; i >= 0; i-- {
+		if err := (*c)[i](ctx); err != nil {
+			logrus.WithError(err).Error("Failed to compensate user service change")
+		}
+	}
+	*c = nil
+}
+
+// createUserAccount creates the login account of a new employee, using the employee code
+// as the username, and registers its removal on undo. It returns the generated password,
+// which the caller hands back once in the create response. Employees that already carry a
+// userId, or a service without a user client, are left as they are and get no password.
+func (s *employeeService) createUserAccount(ctx context.Context, employee *models.Employee, r *models.CreateEmployeeRequest, undo *compensations) (string, error) {
+	if s.userClient == nil || employee.UserID != "" {
+		return "", nil
+	}
+
+	password, err := s.passwordGenerator.Generate()
+	if err != nil {
+		return "", errors.Wrap(err, "INTERNAL_ERROR", "failed to generate password").WithOperation("CreateEmployees")
+	}
+
+	account := &user.User{
+		TenantID: employee.TenantID,
+		UserName: employee.Code,
+		Password: password,
+		// Taken from the request so the user service always gets the plaintext values
+		MobileNumber: r.MobileNumber,
+		Email:        r.Email,
+		Type:         user.TypeEmployee,
+		Active:       employee.IsActive,
+	}
+	if r.Name != nil {
+		account.Name = strings.TrimSpace(r.Name.GivenName + " " + r.Name.FamilyName)
+	}
+
+	created, err := s.userClient.CreateUser(ctx, employee.TenantID, account)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create user account")
+		return "", errors.Wrap(err, "USER_SERVICE_ERROR", "failed to create user account").WithOperation("CreateEmployees")
+	}
+
+	employee.UserID = created.ID
+	undo.add(func(ctx context.Context) error {
+		return s.userClient.DeleteUser(ctx, employee.TenantID, created.ID)
+	})
+	return password, nil
+}
+
+// setUserActive enables or disables the login account of an employee and registers the
+// opposite change on undo
+func (s *employeeService) setUserActive(ctx context.Context, employee *models.Employee, active bool, undo *compensations, op string) error {
+	if s.userClient == nil || employee.UserID == "" {
+		return nil
+	}
+
+	if err := s.userClient.SetActive(ctx, employee.TenantID, employee.UserID, active); err != nil {
+		logrus.WithError(err).Error("Failed to update user account status")
+		return errors.Wrap(err, "USER_SERVICE_ERROR", "failed to update user account status").WithOperation(op)
+	}
+
+	undo.add(func(ctx context.Context) error {
+		return s.userClient.SetActive(ctx, employee.TenantID, employee.UserID, !active)
+	})
+	return nil
+}
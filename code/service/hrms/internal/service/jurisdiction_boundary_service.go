@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"hrms/internal/clients/boundary"
+	"hrms/internal/models"
+	"hrms/pkg/errors"
+)
+
+// maxBoundaryDepth bounds the walk up a boundary hierarchy
+const maxBoundaryDepth = 32
+
+// boundaryBackfillBatchSize caps how many jurisdictions are read per backfill query
+const boundaryBackfillBatchSize = 200
+
+// BoundaryBackfillResult reports the outcome of BackfillBoundaryDetails
+type BoundaryBackfillResult struct {
+	// Filled is the number of jurisdictions whose hierarchy or boundary type was filled
+	Filled int
+	// UnknownBoundary lists jurisdictions whose boundary the boundary service does not
+	// know. They keep an empty hierarchy and boundary type until their boundary is fixed.
+	UnknownBoundary []string
+}
+
+// resolveBoundary validates the boundary of a jurisdiction against the boundary service
+// and fills in what the service knows. Without Boundary the last code of BoundaryRelation
+// is used. Hierarchy and BoundaryType must match the boundary when given and are taken
+// from it otherwise, and BoundaryRelation is replaced by the boundary's ancestor path.
+func (s *jurisdictionService) resolveBoundary(ctx context.Context, j *models.Jurisdiction, op string) error {
+	if j.Boundary == "" {
+		if len(j.BoundaryRelation) == 0 {
+			return errors.NewFieldError("boundary", "boundary or boundaryRelation is required").WithOperation(op)
+		}
+		j.Boundary = j.BoundaryRelation[len(j.BoundaryRelation)-1]
+	}
+
+	field := "boundaryRelation"
+	if len(j.BoundaryRelation) == 0 {
+		field = "boundary"
+	}
+	codes := append(append([]string{}, j.BoundaryRelation...), j.Boundary)
+	found, err := s.lookupBoundaries(ctx, j.TenantID, codes, field, op)
+	if err != nil {
+		return err
+	}
+	if found == nil {
+		// Lenient mode with the boundary service unavailable: keep the request as given
+		if len(j.BoundaryRelation) == 0 {
+			j.BoundaryRelation = []string{j.Boundary}
+		}
+		return nil
+	}
+
+	b := found[j.Boundary]
+	errs := &errors.MultiError{}
+	if j.Hierarchy == "" {
+		j.Hierarchy = b.HierarchyType
+	} else if b.HierarchyType != "" && j.Hierarchy != b.HierarchyType {
+		errs.Add(errors.NewFieldError("hierarchy", fmt.Sprintf("boundary %s belongs to hierarchy %s, not %s", b.Code, b.HierarchyType, j.Hierarchy)).WithOperation(op))
+	}
+	if j.BoundaryType == "" {
+		j.BoundaryType = b.BoundaryType
+	} else if b.BoundaryType != "" && j.BoundaryType != b.BoundaryType {
+		errs.Add(errors.NewFieldError("boundaryType", fmt.Sprintf("boundary %s is of type %s, not %s", b.Code, b.BoundaryType, j.BoundaryType)).WithOperation(op))
+	}
+	if err := errs.ErrorOrNil(); err != nil {
+		return err
+	}
+
+	path, err := s.ancestorPath(ctx, j.TenantID, b, op)
+	if err != nil {
+		return err
+	}
+	if path != nil {
+		j.BoundaryRelation = path
+	}
+	return nil
+}
+
+// BoundaryPath returns the codes from the root of a boundary's hierarchy down to the
+// boundary. In lenient mode with the boundary service unavailable only the boundary
+// itself is returned.
+func (s *jurisdictionService) BoundaryPath(ctx context.Context, code, tenantID string) ([]string, error) {
+	found, err := s.lookupBoundaries(ctx, tenantID, []string{code}, "boundary", "BoundaryPath")
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return []string{code}, nil
+	}
+
+	path, err := s.ancestorPath(ctx, tenantID, found[code], "BoundaryPath")
+	if err != nil {
+		return nil, err
+	}
+	if path == nil {
+		return []string{code}, nil
+	}
+	return path, nil
+}
+
+// BackfillBoundaryDetails fills the hierarchy and boundary type of jurisdictions written
+// before they were stored, taking them from the jurisdiction's boundary. The boundary
+// relation is left as stored. It stops at the first failed boundary service call, in
+// lenient mode too, and is safe to run repeatedly; filled jurisdictions are not read again.
+func (s *jurisdictionService) BackfillBoundaryDetails(ctx context.Context) (*BoundaryBackfillResult, error) {
+	const op = "BackfillBoundaryDetails"
+	result := &BoundaryBackfillResult{}
+
+	// Walk the table by id so jurisdictions that cannot be filled are not read again
+	lastID := "00000000-0000-0000-0000-000000000000"
+	for {
+		batch, err := s.repo.FindMissingBoundaryDetails(ctx, lastID, boundaryBackfillBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) == 0 {
+			return result, nil
+		}
+		lastID = batch[len(batch)-1].ID
+
+		// Look the boundaries up once per tenant
+		codes := make(map[string][]string)
+		seen := make(map[string]bool)
+		for _, j := range batch {
+			if key := j.TenantID + "/" + j.Boundary; !seen[key] {
+				codes[j.TenantID] = append(codes[j.TenantID], j.Boundary)
+				seen[key] = true
+			}
+		}
+		found := make(map[string]map[string]boundary.Boundary, len(codes))
+		for tenantID, tenantCodes := range codes {
+			boundaries, err := s.boundaryClient.SearchByCodes(ctx, tenantID, tenantCodes)
+			if err != nil {
+				return nil, errors.Wrap(err, "BOUNDARY_SERVICE_ERROR", "failed to look up jurisdiction boundaries").WithOperation(op)
+			}
+			found[tenantID] = make(map[string]boundary.Boundary, len(boundaries))
+			for _, b := range boundaries {
+				found[tenantID][b.Code] = b
+			}
+		}
+
+		for _, j := range batch {
+			b, ok := found[j.TenantID][j.Boundary]
+			if !ok {
+				result.UnknownBoundary = append(result.UnknownBoundary, j.ID)
+				continue
+			}
+			if b.HierarchyType == "" && b.BoundaryType == "" {
+				continue
+			}
+			if err := s.repo.FillBoundaryDetails(ctx, j.ID, j.TenantID, b.HierarchyType, b.BoundaryType); err != nil {
+				return nil, err
+			}
+			result.Filled++
+		}
+	}
+}
+
+// lookupBoundaries fetches the given codes and rejects any the boundary service does not
+// know, naming field in the error. It returns nil without an error in lenient mode when
+// the boundary service is unavailable.
+func (s *jurisdictionService) lookupBoundaries(ctx context.Context, tenantID string, codes []string, field, op string) (map[string]boundary.Boundary, error) {
+	boundaries, err := s.boundaryClient.SearchByCodes(ctx, tenantID, codes)
+	if err != nil {
+		return nil, s.boundaryServiceError(err, op)
+	}
+
+	// Create a map of valid codes for quick lookup
+	validCodes := make(map[string]boundary.Boundary, len(boundaries))
+	for _, b := range boundaries {
+		validCodes[b.Code] = b
+	}
+
+	// Check for invalid codes, reporting each one once
+	var invalidCodes []string
+	reported := make(map[string]bool)
+	for _, code := range codes {
+		if _, ok := validCodes[code]; !ok && !reported[code] {
+			invalidCodes = append(invalidCodes, code)
+			reported[code] = true
+		}
+	}
+
+	if len(invalidCodes) > 0 {
+		return nil, errors.New("INVALID_BOUNDARY", fmt.Sprintf("invalid boundary codes for tenant %s: %s",
+			tenantID, strings.Join(invalidCodes, ", "))).
+			WithParams(map[string]string{"field": field, "invalidCodes": strings.Join(invalidCodes, ",")}).
+			WithOperation(op)
+	}
+
+	return validCodes, nil
+}
+
+// ancestorPath returns the codes from the root of b's hierarchy down to b. It returns nil
+// without an error in lenient mode when the boundary service is unavailable.
+func (s *jurisdictionService) ancestorPath(ctx context.Context, tenantID string, b boundary.Boundary, op string) ([]string, error) {
+	path := []string{b.Code}
+	seen := map[string]bool{b.Code: true}
+
+	for parent := b.Parent; parent != ""; {
+		if seen[parent] || len(path) >= maxBoundaryDepth {
+			return nil, errors.New("BOUNDARY_SERVICE_ERROR", fmt.Sprintf("boundary hierarchy of %s is too deep or cyclic", b.Code)).WithOperation(op)
+		}
+
+		found, err := s.boundaryClient.SearchByCodes(ctx, tenantID, []string{parent})
+		if err != nil {
+			return nil, s.boundaryServiceError(err, op)
+		}
+		if len(found) == 0 {
+			return nil, errors.New("BOUNDARY_SERVICE_ERROR", fmt.Sprintf("parent boundary %s of %s not found", parent, b.Code)).WithOperation(op)
+		}
+
+		path = append(path, parent)
+		seen[parent] = true
+		parent = found[0].Parent
+	}
+
+	// Reverse so the path runs from the root down
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, nil
+}
+
+// boundaryServiceError maps a failed boundary service call. In lenient mode the failure
+// is logged and nil is returned so the caller proceeds without the boundary details.
+func (s *jurisdictionService) boundaryServiceError(err error, op string) error {
+	if !s.strictBoundaryValidation {
+		logrus.WithError(err).Warn("Boundary service unavailable, accepting boundary codes unchecked")
+		return nil
+	}
+	logrus.WithError(err).Error("Failed to validate boundary codes")
+	return errors.Wrap(err, "BOUNDARY_SERVICE_ERROR", "failed to validate boundary codes").WithOperation(op)
+}
+
+// applyBoundaryUpdate copies the boundary fields of an update onto a jurisdiction and
+// reports whether any were given. A new boundary or path clears the details derived
+// from the old one so they are looked up again.
+func applyBoundaryUpdate(j *models.Jurisdiction, req *models.UpdateJurisdictionRequest) bool {
+	relationGiven := req.BoundaryRelation != nil && len(*req.BoundaryRelation) > 0
+	if !relationGiven && req.Boundary == "" && req.BoundaryType == "" && req.Hierarchy == "" {
+		return false
+	}
+
+	if relationGiven || req.Boundary != "" {
+		j.Hierarchy, j.BoundaryType, j.Boundary, j.BoundaryRelation = "", "", "", nil
+	}
+	if relationGiven {
+		j.BoundaryRelation = *req.BoundaryRelation
+	}
+	if req.Boundary != "" {
+		j.Boundary = req.Boundary
+	}
+	if req.BoundaryType != "" {
+		j.BoundaryType = req.BoundaryType
+	}
+	if req.Hierarchy != "" {
+		j.Hierarchy = req.Hierarchy
+	}
+	return true
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"hrms/internal/clients/boundary"
+	"hrms/internal/models"
+	"hrms/internal/repository"
+)
+
+// fakeJurisdictionRepo keeps jurisdictions in memory, ordered by id
+type fakeJurisdictionRepo struct {
+	repository.JurisdictionRepository
+	rows []*models.Jurisdiction
+}
+
+func (r *fakeJurisdictionRepo) FindMissingBoundaryDetails(ctx context.Context, afterID string, limit int) ([]*models.Jurisdiction, error) {
+	var found []*models.Jurisdiction
+	for _, j := range r.rows {
+		if j.ID > afterID && j.Boundary != "" && (j.Hierarchy == "" || j.BoundaryType == "") {
+			copied := *j
+			found = append(found, &copied)
+		}
+		if len(found) == limit {
+			break
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeJurisdictionRepo) FillBoundaryDetails(ctx context.Context, id, tenantID, hierarchy, boundaryType string) error {
+	for _, j := range r.rows {
+		if j.ID == id && j.TenantID == tenantID {
+			if j.Hierarchy == "" {
+				j.Hierarchy = hierarchy
+			}
+			if j.BoundaryType == "" {
+				j.BoundaryType = boundaryType
+			}
+		}
+	}
+	return nil
+}
+
+// fakeBoundaryClient serves boundaries by tenant and code and counts lookups
+type fakeBoundaryClient struct {
+	boundaries map[string][]boundary.Boundary
+	fail       bool
+	lookups    int
+}
+
+func (c *fakeBoundaryClient) SearchByCodes(ctx context.Context, tenantID string, codes []string) ([]boundary.Boundary, error) {
+	c.lookups++
+	if c.fail {
+		return nil, stderrors.New("connection refused")
+	}
+	want := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		want[code] = true
+	}
+	var found []boundary.Boundary
+	for _, b := range c.boundaries[tenantID] {
+		if want[b.Code] {
+			found = append(found, b)
+		}
+	}
+	return found, nil
+}
+
+func TestBackfillBoundaryDetails(t *testing.T) {
+	boundaries := map[string][]boundary.Boundary{
+		testTenant: {
+			{Code: "WARD-1", BoundaryType: "Ward", HierarchyType: "ADMIN", Parent: "ZONE-1"},
+			{Code: "ZONE-1", BoundaryType: "Zone", HierarchyType: "ADMIN"},
+		},
+		"pg.cityb": {
+			{Code: "WARD-9", BoundaryType: "Ward", HierarchyType: "REVENUE"},
+		},
+	}
+
+	// manyRows holds more jurisdictions than one backfill batch
+	var manyRows []*models.Jurisdiction
+	for i := 0; i < boundaryBackfillBatchSize+5; i++ {
+		manyRows = append(manyRows, &models.Jurisdiction{ID: fmt.Sprintf("j-%04d", i), Boundary: "WARD-1", TenantID: testTenant})
+	}
+
+	tests := []struct {
+		name        string
+		rows        []*models.Jurisdiction
+		fail        bool
+		wantFilled  int
+		wantUnknown []string
+		// want maps jurisdiction ids to their hierarchy and boundary type after the backfill
+		want        map[string][2]string
+		wantLookups int
+		wantCode    string
+	}{
+		{
+			name:        "nothing to fill",
+			rows:        []*models.Jurisdiction{{ID: "j-1", Hierarchy: "ADMIN", BoundaryType: "Ward", Boundary: "WARD-1", TenantID: testTenant}},
+			want:        map[string][2]string{"j-1": {"ADMIN", "Ward"}},
+			wantLookups: 0,
+		},
+		{
+			name: "fills both from the boundary",
+			rows: []*models.Jurisdiction{
+				{ID: "j-1", Boundary: "WARD-1", TenantID: testTenant},
+				{ID: "j-2", Boundary: "ZONE-1", TenantID: testTenant},
+			},
+			wantFilled:  2,
+			want:        map[string][2]string{"j-1": {"ADMIN", "Ward"}, "j-2": {"ADMIN", "Zone"}},
+			wantLookups: 1,
+		},
+		{
+			name:        "keeps a stored hierarchy",
+			rows:        []*models.Jurisdiction{{ID: "j-1", Hierarchy: "LEGACY", Boundary: "WARD-1", TenantID: testTenant}},
+			wantFilled:  1,
+			want:        map[string][2]string{"j-1": {"LEGACY", "Ward"}},
+			wantLookups: 1,
+		},
+		{
+			name:        "skips rows without a boundary",
+			rows:        []*models.Jurisdiction{{ID: "j-1", TenantID: testTenant}},
+			want:        map[string][2]string{"j-1": {"", ""}},
+			wantLookups: 0,
+		},
+		{
+			name: "looks up per tenant",
+			rows: []*models.Jurisdiction{
+				{ID: "j-1", Boundary: "WARD-1", TenantID: testTenant},
+				{ID: "j-2", Boundary: "WARD-9", TenantID: "pg.cityb"},
+				{ID: "j-3", Boundary: "WARD-1", TenantID: testTenant},
+			},
+			wantFilled:  3,
+			want:        map[string][2]string{"j-1": {"ADMIN", "Ward"}, "j-2": {"REVENUE", "Ward"}, "j-3": {"ADMIN", "Ward"}},
+			wantLookups: 2,
+		},
+		{
+			name: "reports unknown boundaries and boundaries of another tenant",
+			rows: []*models.Jurisdiction{
+				{ID: "j-1", Boundary: "WARD-404", TenantID: testTenant},
+				{ID: "j-2", Boundary: "WARD-9", TenantID: testTenant},
+				{ID: "j-3", Boundary: "WARD-1", TenantID: testTenant},
+			},
+			wantFilled:  1,
+			wantUnknown: []string{"j-1", "j-2"},
+			want:        map[string][2]string{"j-1": {"", ""}, "j-2": {"", ""}, "j-3": {"ADMIN", "Ward"}},
+			wantLookups: 1,
+		},
+		{
+			name:        "walks every batch",
+			rows:        manyRows,
+			wantFilled:  len(manyRows),
+			want:        map[string][2]string{"j-0000": {"ADMIN", "Ward"}, fmt.Sprintf("j-%04d", len(manyRows)-1): {"ADMIN", "Ward"}},
+			wantLookups: 2,
+		},
+		{
+			name:        "boundary service failure stops the backfill",
+			rows:        []*models.Jurisdiction{{ID: "j-1", Boundary: "WARD-1", TenantID: testTenant}},
+			fail:        true,
+			want:        map[string][2]string{"j-1": {"", ""}},
+			wantLookups: 1,
+			wantCode:    "BOUNDARY_SERVICE_ERROR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeJurisdictionRepo{rows: tt.rows}
+			sort.Slice(repo.rows, func(i, j int) bool { return repo.rows[i].ID < repo.rows[j].ID })
+			client := &fakeBoundaryClient{boundaries: boundaries, fail: tt.fail}
+			s := &jurisdictionService{repo: repo, boundaryClient: client}
+
+			result, err := s.BackfillBoundaryDetails(context.Background())
+			if code := errorCode(err); code != tt.wantCode {
+				t.Fatalf("error code = %q, want %q (err: %v)", code, tt.wantCode, err)
+			}
+			if client.lookups != tt.wantLookups {
+				t.Fatalf("boundary lookups = %d, want %d", client.lookups, tt.wantLookups)
+			}
+			if tt.wantCode == "" {
+				if result.Filled != tt.wantFilled {
+					t.Fatalf("filled = %d, want %d", result.Filled, tt.wantFilled)
+				}
+				if fmt.Sprint(result.UnknownBoundary) != fmt.Sprint(tt.wantUnknown) {
+					t.Fatalf("unknown boundary = %v, want %v", result.UnknownBoundary, tt.wantUnknown)
+				}
+			}
+			for _, j := range repo.rows {
+				want, ok := tt.want[j.ID]
+				if !ok {
+					continue
+				}
+				if got := [2]string{j.Hierarchy, j.BoundaryType}; got != want {
+					t.Fatalf("jurisdiction %s has hierarchy and boundary type %v, want %v", j.ID, got, want)
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"time"
+
 	"hrms/internal/models"
 )
 
@@ -13,6 +15,14 @@ type JurisdictionService interface {
 	DeleteJurisdiction(ctx context.Context, id, tenantID string) error
 	SearchJurisdictions(ctx context.Context, criteria *models.JurisdictionSearchCriteria) ([]*models.JurisdictionResponse, error)
 	GetJurisdictionByUUID(ctx context.Context, uuid, tenantID string) (*models.JurisdictionResponse, error)
-	GetJurisdictionsByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.JurisdictionResponse, error)
+	// GetJurisdictionsByEmployeeID lists the jurisdictions of an employee, only those valid at asOf when given
+	GetJurisdictionsByEmployeeID(ctx context.Context, employeeID, tenantID string, asOf *time.Time) ([]*models.JurisdictionResponse, error)
 	ReplaceJurisdiction(ctx context.Context, uuid string, req *models.UpdateJurisdictionRequest, tenantID string) (*models.JurisdictionResponse, error)
+	// ReplaceEmployeeJurisdictions replaces all jurisdictions of an employee with the given ones
+	ReplaceEmployeeJurisdictions(ctx context.Context, employeeID string, reqs []*models.CreateJurisdictionRequest, tenantID string) ([]*models.JurisdictionResponse, error)
+	// BoundaryPath returns the codes from the root of a boundary's hierarchy down to the boundary
+	BoundaryPath(ctx context.Context, code, tenantID string) ([]string, error)
+	// BackfillBoundaryDetails fills the hierarchy and boundary type of jurisdictions
+	// written before they were stored, from the boundary service
+	BackfillBoundaryDetails(ctx context.Context) (*BoundaryBackfillResult, error)
 }
@@ -4,7 +4,6 @@ package service
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,24 +17,30 @@ import (
 
 type jurisdictionService struct {
 	repo           repository.JurisdictionRepository
-	employeeSvc    EmployeeService
-	boundaryClient *boundary.Client
+	employeeRepo   repository.EmployeeRepository
+	boundaryClient boundary.Client
+	// strictBoundaryValidation rejects writes when the boundary service cannot be reached
+	strictBoundaryValidation bool
+	uow                      repository.UnitOfWork
 }
 
-// NewJurisdictionService creates a new jurisdiction service
-func NewJurisdictionService(repo repository.JurisdictionRepository, employeeSvc EmployeeService, boundaryClient *boundary.Client) JurisdictionService {
+// NewJurisdictionService creates a new jurisdiction service. In lenient mode boundary codes
+// are accepted unchecked while the boundary service is unavailable; unknown codes are
+// rejected in both modes.
+func NewJurisdictionService(repo repository.JurisdictionRepository, employeeRepo repository.EmployeeRepository, boundaryClient boundary.Client, strictBoundaryValidation bool, uow repository.UnitOfWork) JurisdictionService {
 	return &jurisdictionService{
-		repo:           repo,
-		employeeSvc:    employeeSvc,
-		boundaryClient: boundaryClient,
+		repo:                     repo,
+		employeeRepo:             employeeRepo,
+		boundaryClient:           boundaryClient,
+		strictBoundaryValidation: strictBoundaryValidation,
+		uow:                      uow,
 	}
 }
 
 func (s *jurisdictionService) CreateJurisdiction(ctx context.Context, req *models.CreateJurisdictionRequest, tenantID string) (*models.JurisdictionResponse, error) {
-	// Validate boundary codes
-	// if err := s.validateBoundaryCodes(ctx, tenantID, req.BoundaryRelation); err != nil {
-	// 	return nil, err
-	// }
+	if err := s.checkEmployee(ctx, req.EmployeeID, tenantID, "CreateJurisdiction"); err != nil {
+		return nil, err
+	}
 
 	// Create jurisdiction model
 	now := time.Now()
@@ -43,7 +48,12 @@ func (s *jurisdictionService) CreateJurisdiction(ctx context.Context, req *model
 	jurisdiction := &models.Jurisdiction{
 		ID:               uuid.New().String(),
 		EmployeeID:       req.EmployeeID,
+		Hierarchy:        req.Hierarchy,
+		BoundaryType:     req.BoundaryType,
+		Boundary:         req.Boundary,
 		BoundaryRelation: req.BoundaryRelation,
+		ValidFrom:        req.ValidFrom,
+		ValidTo:          req.ValidTo,
 		IsActive:         true, // Default to true if not provided
 		TenantID:         tenantID,
 		CreatedTime:      now.Unix(),
@@ -55,43 +65,114 @@ func (s *jurisdictionService) CreateJurisdiction(ctx context.Context, req *model
 		jurisdiction.IsActive = *req.IsActive
 	}
 
+	// Jurisdictions hold from the time of creation unless a start is given
+	if jurisdiction.ValidFrom == nil {
+		validFrom := now.UTC()
+		jurisdiction.ValidFrom = &validFrom
+	}
+	if err := validatePeriod(jurisdiction, "CreateJurisdiction"); err != nil {
+		return nil, err
+	}
+
+	// Validate the boundary and derive its ancestor path
+	if err := s.resolveBoundary(ctx, jurisdiction, "CreateJurisdiction"); err != nil {
+		return nil, err
+	}
+	if err := s.checkDuplicate(ctx, jurisdiction, "CreateJurisdiction"); err != nil {
+		return nil, err
+	}
+
 	// Save to database
 	if err := s.repo.Create(ctx, jurisdiction); err != nil {
-		return nil, fmt.Errorf("failed to create jurisdiction: %w", err)
+		logrus.WithError(err).Error("Failed to create jurisdiction")
+		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to create jurisdiction").WithOperation("CreateJurisdiction")
 	}
 
 	return toJurisdictionResponse(jurisdiction), nil
 }
 
-func (s *jurisdictionService) validateBoundaryCodes(ctx context.Context, tenantID string, codes []string) error {
-	if len(codes) == 0 {
-		return nil
-	}
+// ReplaceEmployeeJurisdictions replaces all jurisdictions of an employee with the given
+// ones in a single transaction. The current jurisdictions are closed rather than deleted,
+// so they remain visible to searches as of an earlier date.
+func (s *jurisdictionService) ReplaceEmployeeJurisdictions(ctx context.Context, employeeID string, reqs []*models.CreateJurisdictionRequest, tenantID string) ([]*models.JurisdictionResponse, error) {
+	responses := make([]*models.JurisdictionResponse, 0, len(reqs))
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.checkEmployee(ctx, employeeID, tenantID, "ReplaceEmployeeJurisdictions"); err != nil {
+			return err
+		}
 
-	boundaries, err := s.boundaryClient.SearchByCodes(ctx, tenantID, codes)
+		now := time.Now().UTC()
+		if err := s.repo.CloseByEmployeeID(ctx, employeeID, tenantID, now); err != nil {
+			logrus.WithError(err).Error("Failed to close jurisdictions of employee")
+			return errors.Wrap(err, "DATABASE_ERROR", "failed to close jurisdictions of employee").WithOperation("ReplaceEmployeeJurisdictions")
+		}
+
+		for i, req := range reqs {
+			req.EmployeeID = employeeID
+			if req.ValidFrom == nil {
+				req.ValidFrom = &now
+			}
+			created, err := s.CreateJurisdiction(ctx, req, tenantID)
+			if err != nil {
+				return withItemIndex(err, i)
+			}
+			responses = append(responses, created)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to validate boundary codes: %w", err)
+		return nil, err
 	}
 
-	// Create a map of valid codes for quick lookup
-	validCodes := make(map[string]bool)
-	for _, b := range boundaries {
-		validCodes[b.Code] = true
+	return responses, nil
+}
+
+// checkEmployee makes sure the employee exists in the tenant, so a jurisdiction can never
+// be attached to a missing employee or to one of another tenant
+func (s *jurisdictionService) checkEmployee(ctx context.Context, employeeID, tenantID, op string) error {
+	if employeeID == "" {
+		return errors.NewFieldError("employeeId", "employee ID is required").WithOperation(op)
+	}
+	if _, err := uuid.Parse(employeeID); err != nil {
+		return errors.NewFieldError("employeeId", "employee ID must be a UUID").WithOperation(op)
 	}
 
-	// Check for invalid codes
-	var invalidCodes []string
-	for _, code := range codes {
-		if !validCodes[code] {
-			invalidCodes = append(invalidCodes, code)
+	if _, err := s.employeeRepo.FindByUUID(ctx, employeeID, tenantID); err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return errors.ErrEmployeeNotFound.WithDescription(fmt.Sprintf("employee %s not found", employeeID)).WithOperation(op)
 		}
+		logrus.WithError(err).Error("Failed to find employee")
+		return errors.Wrap(err, "DATABASE_ERROR", "failed to find employee").WithOperation(op)
 	}
+	return nil
+}
 
-	if len(invalidCodes) > 0 {
-		return fmt.Errorf("invalid boundary codes for tenant %s: %s",
-			tenantID, strings.Join(invalidCodes, ", "))
+// checkDuplicate rejects a second active jurisdiction of an employee over the same boundary
+// in an overlapping period
+func (s *jurisdictionService) checkDuplicate(ctx context.Context, j *models.Jurisdiction, op string) error {
+	if !j.IsActive {
+		return nil
 	}
 
+	active := true
+	existing, err := s.repo.Search(ctx, &models.JurisdictionSearchCriteria{
+		EmployeeID: j.EmployeeID,
+		IsActive:   &active,
+		TenantID:   j.TenantID,
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to check for duplicate jurisdictions")
+		return errors.Wrap(err, "DATABASE_ERROR", "failed to check for duplicate jurisdictions").WithOperation(op)
+	}
+
+	for _, e := range existing {
+		if e.ID != j.ID && e.Boundary == j.Boundary && overlaps(e, j) {
+			return errors.ErrJurisdictionExists.
+				WithDescription(fmt.Sprintf("employee already has an active jurisdiction over boundary %s", j.Boundary)).
+				WithOperation(op)
+		}
+	}
 	return nil
 }
 
@@ -108,21 +189,18 @@ func (s *jurisdictionService) GetJurisdictionByUUID(ctx context.Context, uuid, t
 	return toJurisdictionResponse(jurisdiction), nil
 }
 
-// GetJurisdictionsByEmployeeID retrieves all jurisdictions for a specific employee
-func (s *jurisdictionService) GetJurisdictionsByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.JurisdictionResponse, error) {
+// GetJurisdictionsByEmployeeID retrieves all jurisdictions for a specific employee, or only
+// those valid at asOf when it is given
+func (s *jurisdictionService) GetJurisdictionsByEmployeeID(ctx context.Context, employeeID, tenantID string, asOf *time.Time) ([]*models.JurisdictionResponse, error) {
 	// First, verify the employee exists
-	_, err := s.employeeSvc.GetEmployeeByUUID(ctx, employeeID, tenantID)
-	if err != nil {
-		if errors.Is(err, errors.ErrNotFound) {
-			return nil, errors.New("NOT_FOUND", "employee not found").WithOperation("GetJurisdictionsByEmployeeID")
-		}
-		logrus.WithError(err).Error("Failed to validate employee")
-		return nil, errors.Wrap(err, "VALIDATION_ERROR", "failed to validate employee").WithOperation("GetJurisdictionsByEmployeeID")
+	if err := s.checkEmployee(ctx, employeeID, tenantID, "GetJurisdictionsByEmployeeID"); err != nil {
+		return nil, err
 	}
 
 	// Create search criteria
 	criteria := &models.JurisdictionSearchCriteria{
 		EmployeeIDs: []string{employeeID},
+		AsOf:        asOf,
 		TenantID:    tenantID,
 	}
 
@@ -168,17 +246,36 @@ func (s *jurisdictionService) UpdateJurisdiction(ctx context.Context, uuid strin
 		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to find jurisdiction").WithOperation("UpdateJurisdiction")
 	}
 
-	if req.EmployeeID != "" {
+	if req.EmployeeID != "" && req.EmployeeID != existing.EmployeeID {
+		if err := s.checkEmployee(ctx, req.EmployeeID, tenantID, "UpdateJurisdiction"); err != nil {
+			return nil, err
+		}
 		existing.EmployeeID = req.EmployeeID
 	}
 
-	if req.BoundaryRelation != nil && len(*req.BoundaryRelation) > 0 {
-		existing.BoundaryRelation = *req.BoundaryRelation
+	if applyBoundaryUpdate(existing, req) {
+		if err := s.resolveBoundary(ctx, existing, "UpdateJurisdiction"); err != nil {
+			return nil, err
+		}
 	}
 
 	if req.IsActive != nil {
 		existing.IsActive = *req.IsActive
 	}
+
+	if req.ValidFrom != nil {
+		existing.ValidFrom = req.ValidFrom
+	}
+	if req.ValidTo != nil {
+		existing.ValidTo = req.ValidTo
+	}
+	if err := validatePeriod(existing, "UpdateJurisdiction"); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkDuplicate(ctx, existing, "UpdateJurisdiction"); err != nil {
+		return nil, err
+	}
 	// existing.LastModifiedTime = time.Now()
 
 	// Save changes
@@ -190,6 +287,23 @@ func (s *jurisdictionService) UpdateJurisdiction(ctx context.Context, uuid strin
 	return toJurisdictionResponse(existing), nil
 }
 
+// validatePeriod checks that a jurisdiction ends after it starts
+func validatePeriod(j *models.Jurisdiction, op string) error {
+	if j.ValidFrom != nil && j.ValidTo != nil && !j.ValidTo.After(*j.ValidFrom) {
+		return errors.NewFieldError("validTo", "validTo must be after validFrom").WithOperation(op)
+	}
+	return nil
+}
+
+// overlaps reports whether the validity periods of two jurisdictions overlap. An open
+// start or end is unbounded.
+func overlaps(a, b *models.Jurisdiction) bool {
+	startsBeforeEnd := func(x, y *models.Jurisdiction) bool {
+		return x.ValidFrom == nil || y.ValidTo == nil || x.ValidFrom.Before(*y.ValidTo)
+	}
+	return startsBeforeEnd(a, b) && startsBeforeEnd(b, a)
+}
+
 // toJurisdictionResponse converts a Jurisdiction model to JurisdictionResponse
 func toJurisdictionResponse(j *models.Jurisdiction) *models.JurisdictionResponse {
 	if j == nil {
@@ -199,11 +313,22 @@ func toJurisdictionResponse(j *models.Jurisdiction) *models.JurisdictionResponse
 	return &models.JurisdictionResponse{
 		ID:               j.ID,
 		EmployeeID:       j.EmployeeID,
+		Hierarchy:        j.Hierarchy,
+		BoundaryType:     j.BoundaryType,
+		Boundary:         j.Boundary,
 		BoundaryRelation: j.BoundaryRelation,
+		ValidFrom:        j.ValidFrom,
+		ValidTo:          j.ValidTo,
 		IsActive:         j.IsActive,
 		TenantID:         j.TenantID,
 		CreatedTime:      j.CreatedTime,
 		LastModifiedTime: j.LastModifiedTime,
+		AuditDetails: &models.AuditDetails{
+			CreatedBy:        j.CreatedBy,
+			LastModifiedBy:   j.LastModifiedBy,
+			CreatedTime:      j.CreatedTime,
+			LastModifiedTime: j.LastModifiedTime,
+		},
 	}
 }
 
@@ -241,13 +366,8 @@ func (s *jurisdictionService) ReplaceJurisdiction(ctx context.Context, uuid stri
 
 	// Validate employee exists if EmployeeID is being updated
 	if req.EmployeeID != "" && req.EmployeeID != existing.EmployeeID {
-		_, err := s.employeeSvc.GetEmployeeByUUID(ctx, req.EmployeeID, tenantID)
-		if err != nil {
-			if errors.Is(err, errors.ErrNotFound) {
-				return nil, errors.New("NOT_FOUND", "employee not found").WithOperation("ReplaceJurisdiction")
-			}
-			logrus.WithError(err).Error("Failed to validate employee")
-			return nil, errors.Wrap(err, "VALIDATION_ERROR", "failed to validate employee").WithOperation("ReplaceJurisdiction")
+		if err := s.checkEmployee(ctx, req.EmployeeID, tenantID, "ReplaceJurisdiction"); err != nil {
+			return nil, err
 		}
 	}
 
@@ -256,15 +376,29 @@ func (s *jurisdictionService) ReplaceJurisdiction(ctx context.Context, uuid stri
 		existing.EmployeeID = req.EmployeeID
 	}
 
-	if req.BoundaryRelation != nil && len(*req.BoundaryRelation) > 0 {
-		existing.BoundaryRelation = *req.BoundaryRelation
+	if applyBoundaryUpdate(existing, req) {
+		if err := s.resolveBoundary(ctx, existing, "ReplaceJurisdiction"); err != nil {
+			return nil, err
+		}
 	}
 
 	if req.IsActive != nil {
 		existing.IsActive = *req.IsActive
 	}
 
-	// existing.LastModifiedTime = time.Now()
+	if req.ValidFrom != nil {
+		existing.ValidFrom = req.ValidFrom
+	}
+	if req.ValidTo != nil {
+		existing.ValidTo = req.ValidTo
+	}
+	if err := validatePeriod(existing, "ReplaceJurisdiction"); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkDuplicate(ctx, existing, "ReplaceJurisdiction"); err != nil {
+		return nil, err
+	}
 
 	// Save changes
 	if err := s.repo.Update(ctx, existing); err != nil {
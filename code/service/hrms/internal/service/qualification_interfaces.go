@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"hrms/internal/models"
+)
+
+// QualificationService defines the interface for employee educational details and departmental tests
+type QualificationService interface {
+	// ValidateQualifications checks educational details and departmental tests for missing fields
+	ValidateQualifications(education []*models.EducationalDetail, tests []*models.DepartmentalTest) error
+
+	// CreateQualifications adds educational details and departmental tests to an employee
+	CreateQualifications(ctx context.Context, employeeID string, education []*models.EducationalDetail, tests []*models.DepartmentalTest, tenantID string) error
+
+	// ReplaceQualifications replaces all educational details and departmental tests of an employee.
+	// A nil list is left as stored; an empty one removes every entry.
+	ReplaceQualifications(ctx context.Context, employeeID string, education []*models.EducationalDetail, tests []*models.DepartmentalTest, tenantID string) error
+
+	// GetEducationalDetailsByEmployeeID retrieves all educational details of an employee
+	GetEducationalDetailsByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.EducationalDetail, error)
+
+	// GetDepartmentalTestsByEmployeeID retrieves all departmental tests of an employee
+	GetDepartmentalTestsByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.DepartmentalTest, error)
+}
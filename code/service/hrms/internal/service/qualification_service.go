@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"hrms/internal/models"
+	"hrms/internal/repository"
+	"hrms/pkg/errors"
+)
+
+type qualificationService struct {
+	repo repository.QualificationRepository
+}
+
+// NewQualificationService creates a new qualification service
+func NewQualificationService(repo repository.QualificationRepository) QualificationService {
+	return &qualificationService{
+		repo: repo,
+	}
+}
+
+// ValidateQualifications checks educational details and departmental tests for missing fields
+func (s *qualificationService) ValidateQualifications(education []*models.EducationalDetail, tests []*models.DepartmentalTest) error {
+	maxYear := time.Now().Year()
+
+	for _, e := range education {
+		if e.Degree == "" {
+			return errors.New("VALIDATION_ERROR", "degree is required for every educational detail")
+		}
+		if e.YearOfPassing < 1900 || e.YearOfPassing > maxYear {
+			return errors.New("VALIDATION_ERROR", "invalid yearOfPassing in educational details")
+		}
+	}
+
+	for _, t := range tests {
+		if t.Test == "" {
+			return errors.New("VALIDATION_ERROR", "test is required for every departmental test")
+		}
+		if t.YearOfPassing < 1900 || t.YearOfPassing > maxYear {
+			return errors.New("VALIDATION_ERROR", "invalid yearOfPassing in departmental tests")
+		}
+	}
+
+	return nil
+}
+
+// CreateQualifications adds educational details and departmental tests to an employee
+func (s *qualificationService) CreateQualifications(ctx context.Context, employeeID string, education []*models.EducationalDetail, tests []*models.DepartmentalTest, tenantID string) error {
+	if err := s.ValidateQualifications(education, tests); err != nil {
+		return err
+	}
+
+	for _, e := range education {
+		detail := &models.EducationalDetail{
+			ID:             uuid.New().String(),
+			EmployeeID:     employeeID,
+			Degree:         e.Degree,
+			Specialization: e.Specialization,
+			University:     e.University,
+			YearOfPassing:  e.YearOfPassing,
+			Remarks:        e.Remarks,
+			TenantID:       tenantID,
+			CreatedBy:      "system",
+		}
+		if err := s.repo.CreateEducationalDetail(ctx, detail); err != nil {
+			logrus.WithError(err).Error("Failed to create educational detail")
+			return errors.Wrap(err, "DATABASE_ERROR", "failed to create educational detail").WithOperation("CreateQualifications")
+		}
+	}
+
+	for _, t := range tests {
+		test := &models.DepartmentalTest{
+			ID:            uuid.New().String(),
+			EmployeeID:    employeeID,
+			Test:          t.Test,
+			YearOfPassing: t.YearOfPassing,
+			Remarks:       t.Remarks,
+			TenantID:      tenantID,
+			CreatedBy:     "system",
+		}
+		if err := s.repo.CreateDepartmentalTest(ctx, test); err != nil {
+			logrus.WithError(err).Error("Failed to create departmental test")
+			return errors.Wrap(err, "DATABASE_ERROR", "failed to create departmental test").WithOperation("CreateQualifications")
+		}
+	}
+
+	return nil
+}
+
+// ReplaceQualifications replaces all educational details and departmental tests of an
+// employee. A nil list is left as stored; an empty one removes every entry.
+func (s *qualificationService) ReplaceQualifications(ctx context.Context, employeeID string, education []*models.EducationalDetail, tests []*models.DepartmentalTest, tenantID string) error {
+	if err := s.ValidateQualifications(education, tests); err != nil {
+		return err
+	}
+
+	if education != nil {
+		if err := s.repo.DeleteEducationalDetailsByEmployeeID(ctx, employeeID, tenantID); err != nil {
+			logrus.WithError(err).Error("Failed to delete educational details")
+			return errors.Wrap(err, "DATABASE_ERROR", "failed to delete educational details").WithOperation("ReplaceQualifications")
+		}
+	}
+
+	if tests != nil {
+		if err := s.repo.DeleteDepartmentalTestsByEmployeeID(ctx, employeeID, tenantID); err != nil {
+			logrus.WithError(err).Error("Failed to delete departmental tests")
+			return errors.Wrap(err, "DATABASE_ERROR", "failed to delete departmental tests").WithOperation("ReplaceQualifications")
+		}
+	}
+
+	return s.CreateQualifications(ctx, employeeID, education, tests, tenantID)
+}
+
+// GetEducationalDetailsByEmployeeID retrieves all educational details of an employee
+func (s *qualificationService) GetEducationalDetailsByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.EducationalDetail, error) {
+	details, err := s.repo.FindEducationalDetailsByEmployeeID(ctx, employeeID, tenantID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get educational details by employee ID")
+		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to get educational details by employee ID").WithOperation("GetEducationalDetailsByEmployeeID")
+	}
+	return details, nil
+}
+
+// GetDepartmentalTestsByEmployeeID retrieves all departmental tests of an employee
+func (s *qualificationService) GetDepartmentalTestsByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.DepartmentalTest, error) {
+	tests, err := s.repo.FindDepartmentalTestsByEmployeeID(ctx, employeeID, tenantID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get departmental tests by employee ID")
+		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to get departmental tests by employee ID").WithOperation("GetDepartmentalTestsByEmployeeID")
+	}
+	return tests, nil
+}
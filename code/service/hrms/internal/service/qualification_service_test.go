@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"hrms/internal/models"
+	"hrms/internal/repository"
+)
+
+// fakeQualificationRepo keeps the qualifications of a single employee in memory
+type fakeQualificationRepo struct {
+	repository.QualificationRepository
+	education []*models.EducationalDetail
+	tests     []*models.DepartmentalTest
+}
+
+func (r *fakeQualificationRepo) CreateEducationalDetail(ctx context.Context, detail *models.EducationalDetail) error {
+	r.education = append(r.education, detail)
+	return nil
+}
+
+func (r *fakeQualificationRepo) FindEducationalDetailsByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.EducationalDetail, error) {
+	return r.education, nil
+}
+
+func (r *fakeQualificationRepo) DeleteEducationalDetailsByEmployeeID(ctx context.Context, employeeID, tenantID string) error {
+	r.education = nil
+	return nil
+}
+
+func (r *fakeQualificationRepo) CreateDepartmentalTest(ctx context.Context, test *models.DepartmentalTest) error {
+	r.tests = append(r.tests, test)
+	return nil
+}
+
+func (r *fakeQualificationRepo) FindDepartmentalTestsByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.DepartmentalTest, error) {
+	return r.tests, nil
+}
+
+func (r *fakeQualificationRepo) DeleteDepartmentalTestsByEmployeeID(ctx context.Context, employeeID, tenantID string) error {
+	r.tests = nil
+	return nil
+}
+
+func TestReplaceQualifications(t *testing.T) {
+	const id = "emp-1"
+	be := &models.EducationalDetail{Degree: "BE", YearOfPassing: 2010}
+	me := &models.EducationalDetail{Degree: "ME", YearOfPassing: 2012}
+	accounts := &models.DepartmentalTest{Test: "Accounts", YearOfPassing: 2015}
+
+	tests := []struct {
+		name          string
+		education     []*models.EducationalDetail
+		tests         []*models.DepartmentalTest
+		wantEducation []string
+		wantTests     []string
+		wantCode      string
+	}{
+		{name: "nil lists are kept", wantEducation: []string{"BSc"}, wantTests: []string{"Language"}},
+		{name: "empty lists are cleared", education: []*models.EducationalDetail{}, tests: []*models.DepartmentalTest{}},
+		{name: "education replaced, tests kept", education: []*models.EducationalDetail{be, me}, wantEducation: []string{"BE", "ME"}, wantTests: []string{"Language"}},
+		{name: "tests replaced, education kept", tests: []*models.DepartmentalTest{accounts}, wantEducation: []string{"BSc"}, wantTests: []string{"Accounts"}},
+		{name: "education cleared, tests kept", education: []*models.EducationalDetail{}, wantTests: []string{"Language"}},
+		{
+			name:          "invalid entry leaves both unchanged",
+			education:     []*models.EducationalDetail{{YearOfPassing: 2010}},
+			tests:         []*models.DepartmentalTest{},
+			wantEducation: []string{"BSc"},
+			wantTests:     []string{"Language"},
+			wantCode:      "VALIDATION_ERROR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeQualificationRepo{
+				education: []*models.EducationalDetail{{ID: "ed-1", EmployeeID: id, Degree: "BSc", YearOfPassing: 2008}},
+				tests:     []*models.DepartmentalTest{{ID: "dt-1", EmployeeID: id, Test: "Language", YearOfPassing: 2009}},
+			}
+			s := NewQualificationService(repo)
+
+			err := s.ReplaceQualifications(context.Background(), id, tt.education, tt.tests, testTenant)
+			if code := errorCode(err); code != tt.wantCode {
+				t.Fatalf("error code = %q, want %q (err: %v)", code, tt.wantCode, err)
+			}
+
+			var education, tests []string
+			for _, e := range repo.education {
+				education = append(education, e.Degree)
+			}
+			for _, dt := range repo.tests {
+				tests = append(tests, dt.Test)
+			}
+			if fmt.Sprint(education) != fmt.Sprint(tt.wantEducation) {
+				t.Fatalf("education = %v, want %v", education, tt.wantEducation)
+			}
+			if fmt.Sprint(tests) != fmt.Sprint(tt.wantTests) {
+				t.Fatalf("tests = %v, want %v", tests, tt.wantTests)
+			}
+		})
+	}
+}
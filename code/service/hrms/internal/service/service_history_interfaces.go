@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+	"hrms/internal/models"
+)
+
+// ServiceHistoryService defines the interface for employee service history operations
+type ServiceHistoryService interface {
+	// ValidateServiceHistory checks a set of service history records for invalid periods.
+	// Overlapping periods are allowed.
+	ValidateServiceHistory(records []*models.ServiceHistory) error
+
+	// CreateServiceHistory adds the given service history records to an employee
+	CreateServiceHistory(ctx context.Context, employeeID string, records []*models.ServiceHistory, tenantID string) ([]*models.ServiceHistory, error)
+
+	// ReplaceServiceHistory replaces all service history records of an employee
+	ReplaceServiceHistory(ctx context.Context, employeeID string, records []*models.ServiceHistory, tenantID string) ([]*models.ServiceHistory, error)
+
+	// GetServiceHistoryByEmployeeID retrieves all service history records of an employee
+	GetServiceHistoryByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.ServiceHistory, error)
+}
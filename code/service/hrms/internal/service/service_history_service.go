@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"hrms/internal/models"
+	"hrms/internal/repository"
+	"hrms/pkg/errors"
+)
+
+type serviceHistoryService struct {
+	repo repository.ServiceHistoryRepository
+}
+
+// NewServiceHistoryService creates a new service history service
+func NewServiceHistoryService(repo repository.ServiceHistoryRepository) ServiceHistoryService {
+	return &serviceHistoryService{
+		repo: repo,
+	}
+}
+
+// ValidateServiceHistory only checks that each period is well formed; periods of
+// different records are free to overlap
+func (s *serviceHistoryService) ValidateServiceHistory(records []*models.ServiceHistory) error {
+	for _, r := range records {
+		if r.ServiceFrom != nil && r.ServiceTo != nil && r.ServiceTo.Before(*r.ServiceFrom) {
+			return errors.New("VALIDATION_ERROR", "service history serviceTo must not be before serviceFrom")
+		}
+	}
+	return nil
+}
+
+// CreateServiceHistory adds the given service history records to an employee
+func (s *serviceHistoryService) CreateServiceHistory(ctx context.Context, employeeID string, records []*models.ServiceHistory, tenantID string) ([]*models.ServiceHistory, error) {
+	if err := s.ValidateServiceHistory(records); err != nil {
+		return nil, err
+	}
+
+	for _, r := range records {
+		record := &models.ServiceHistory{
+			ID:                uuid.New().String(),
+			EmployeeID:        employeeID,
+			ServiceStatus:     r.ServiceStatus,
+			ServiceFrom:       r.ServiceFrom,
+			ServiceTo:         r.ServiceTo,
+			OrderNo:           r.OrderNo,
+			Location:          r.Location,
+			IsCurrentPosition: r.IsCurrentPosition,
+			TenantID:          tenantID,
+			CreatedBy:         "system",
+		}
+		if err := s.repo.Create(ctx, record); err != nil {
+			logrus.WithError(err).Error("Failed to create service history")
+			return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to create service history").WithOperation("CreateServiceHistory")
+		}
+	}
+
+	return s.GetServiceHistoryByEmployeeID(ctx, employeeID, tenantID)
+}
+
+// ReplaceServiceHistory replaces all service history records of an employee
+func (s *serviceHistoryService) ReplaceServiceHistory(ctx context.Context, employeeID string, records []*models.ServiceHistory, tenantID string) ([]*models.ServiceHistory, error) {
+	if err := s.ValidateServiceHistory(records); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.DeleteByEmployeeID(ctx, employeeID, tenantID); err != nil {
+		logrus.WithError(err).Error("Failed to delete service history")
+		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to delete service history").WithOperation("ReplaceServiceHistory")
+	}
+
+	return s.CreateServiceHistory(ctx, employeeID, records, tenantID)
+}
+
+// GetServiceHistoryByEmployeeID retrieves all service history records of an employee
+func (s *serviceHistoryService) GetServiceHistoryByEmployeeID(ctx context.Context, employeeID, tenantID string) ([]*models.ServiceHistory, error) {
+	records, err := s.repo.FindByEmployeeID(ctx, employeeID, tenantID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get service history by employee ID")
+		return nil, errors.Wrap(err, "DATABASE_ERROR", "failed to get service history by employee ID").WithOperation("GetServiceHistoryByEmployeeID")
+	}
+	return records, nil
+}
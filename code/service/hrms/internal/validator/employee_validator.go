@@ -9,15 +9,26 @@ import (
 	"hrms/internal/config"
 	"hrms/internal/models"
 	"hrms/internal/repository"
+	"hrms/pkg/errors"
 
 	"github.com/go-playground/validator/v10"
 )
 
+// maxSearchLimit is the largest page size accepted by employee search
+const maxSearchLimit = 100
+
+// maxSearchBoundaryCodes caps the boundaries in one employee search
+const maxSearchBoundaryCodes = 50
+
 var (
 	// phoneRegex validates 10-digit phone numbers starting with 6-9
 	phoneRegex = regexp.MustCompile(`^[6-9][0-9]{9}$`)
 	// emailRegex validates standard email format
 	emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
+	// panRegex validates Permanent Account Numbers, e.g. ABCDE1234F
+	panRegex = regexp.MustCompile(`^[A-Z]{5}[0-9]{4}[A-Z]$`)
+	// aadhaarRegex validates 12-digit Aadhaar numbers
+	aadhaarRegex = regexp.MustCompile(`^[0-9]{12}$`)
 )
 
 // EmployeeValidator validates employee data against the OpenAPI specification
@@ -44,84 +55,114 @@ func NewEmployeeValidator(repo repository.EmployeeRepository, cfg *config.Config
 	}
 }
 
-// ValidateCreate validates a new employee creation request
+// ValidateCreate validates a new employee creation request. Every invalid field is
+// reported in the returned *errors.MultiError.
 func (v *EmployeeValidator) ValidateCreate(ctx context.Context, emp *models.Employee) error {
-	// Required fields validation
-	if emp.TenantID == "" {
-		return fmt.Errorf("tenant ID is required")
-	}
-
-	if emp.EmployeeType == "" {
-		return fmt.Errorf("employee type is required")
-	}
-
-	if !isValidEmployeeType(emp.EmployeeType) {
-		return fmt.Errorf("invalid employee type: %s. Must be one of: PERMANENT, CONTRACT, TEMPORARY", emp.EmployeeType)
-	}
+	errs := &errors.MultiError{}
+	v.validateEmployee(ctx, emp, errs)
+	return errs.ErrorOrNil()
+}
 
-	if emp.Department == "" {
-		return fmt.Errorf("department is required")
+// ValidateUpdate validates an employee replacement (PUT) request. The code, which is the
+// login username, and the user account cannot be changed once set, and isActive only
+// changes through deactivation and reactivation.
+func (v *EmployeeValidator) ValidateUpdate(ctx context.Context, emp *models.Employee, existing *models.Employee) error {
+	if existing == nil {
+		return errors.ErrNotFound.WithDescription("employee not found")
 	}
 
-	if emp.Designation == "" {
-		return fmt.Errorf("designation is required")
+	errs := &errors.MultiError{}
+	if existing.Code != "" && emp.Code != existing.Code {
+		errs.Add(errors.NewFieldError("code", "code cannot be changed"))
 	}
-
-	// Validate field formats and constraints
-	if emp.Code != "" {
-		if len(emp.Code) < 2 || len(emp.Code) > 64 {
-			return fmt.Errorf("code must be between 2 and 64 characters")
-		}
+	if existing.UserID != "" && emp.UserID != existing.UserID {
+		errs.Add(errors.NewFieldError("userId", "userId cannot be changed"))
 	}
-
-	// Check for duplicate employee code
-	if emp.Code != "" {
-		existing, err := v.repo.FindByCode(ctx, emp.Code, emp.TenantID)
-		if err == nil && existing != nil && existing.ID != emp.ID {
-			return fmt.Errorf("employee with code %s already exists", emp.Code)
-		}
+	if emp.IsActive != existing.IsActive {
+		errs.Add(isActiveChangeError())
 	}
-
-	return nil
+	v.validateEmployee(ctx, emp, errs)
+	return errs.ErrorOrNil()
 }
 
-// ValidateUpdate validates an employee update request
-func (v *EmployeeValidator) ValidateUpdate(ctx context.Context, emp *models.Employee, existing *models.Employee) error {
-	if existing == nil {
-		return fmt.Errorf("employee not found")
+// ValidatePatch validates a partial employee update against the stored employee. As on
+// PUT, isActive only changes through deactivation and reactivation.
+func (v *EmployeeValidator) ValidatePatch(ctx context.Context, patch *models.UpdateEmployeeRequest, existing *models.Employee) error {
+	errs := &errors.MultiError{}
+
+	if patch.IsActive != nil && *patch.IsActive != existing.IsActive {
+		errs.Add(isActiveChangeError())
 	}
-	return nil
-}
 
-// ValidatePatch validates a partial employee update
-func (v *EmployeeValidator) ValidatePatch(patch *models.UpdateEmployeeRequest) error {
 	if patch.EmployeeType != nil {
 		if !isValidEmployeeType(*patch.EmployeeType) {
-			return fmt.Errorf("invalid employee type: %s. Must be one of: PERMANENT, CONTRACT, TEMPORARY", *patch.EmployeeType)
+			errs.Add(errors.NewFieldError("employeeType", fmt.Sprintf("invalid employee type: %s. Must be one of: PERMANENT, CONTRACT, TEMPORARY", *patch.EmployeeType)))
 		}
 	}
 
 	if patch.EmployeeStatus != nil {
 		if !isValidEmployeeStatus(*patch.EmployeeStatus) {
-			return fmt.Errorf("invalid employee status: %s. Must be one of: ACTIVE, INACTIVE, SUSPENDED", *patch.EmployeeStatus)
+			errs.Add(errors.NewFieldError("employeeStatus", fmt.Sprintf("invalid employee status: %s. Must be one of: ACTIVE, INACTIVE, SUSPENDED", *patch.EmployeeStatus)))
 		}
 	}
 
-	if patch.Phone != nil && !phoneRegex.MatchString(*patch.Phone) {
-		return fmt.Errorf("invalid mobile number format. Must be a 10-digit number starting with 6-9")
+	// Personal details are checked on the employee as it will look after the patch
+	patched := *existing
+	if patch.Phone != nil {
+		patched.MobileNumber = *patch.Phone
+	}
+	if patch.EmailId != nil {
+		patched.Email = *patch.EmailId
+	}
+	if patch.DateOfBirth != nil {
+		patched.DateOfBirth = patch.DateOfBirth
+	}
+	if patch.Gender != nil {
+		patched.Gender = *patch.Gender
 	}
+	if patch.PanNumber != nil {
+		patched.PanNumber = *patch.PanNumber
+	}
+	if patch.AadhaarNumber != nil {
+		patched.AadhaarNumber = *patch.AadhaarNumber
+	}
+	if patch.DateOfRetirement != nil {
+		patched.DateOfRetirement = patch.DateOfRetirement
+	}
+	v.validatePersonalDetails(ctx, &patched, errs)
 
-	if patch.EmailId != nil && !emailRegex.MatchString(*patch.EmailId) {
-		return fmt.Errorf("invalid email format")
+	return errs.ErrorOrNil()
+}
+
+// isActiveChangeError rejects a change of isActive outside the status endpoints, which
+// record the status history and enable or disable the login account with it
+func isActiveChangeError() *errors.Error {
+	return errors.NewFieldError("isActive", "isActive cannot be changed here; use POST /employees/v3/{id}/deactivate or /reactivate")
+}
+
+// ValidateAuditSearch validates audit log search criteria, defaulting the page size
+func (v *EmployeeValidator) ValidateAuditSearch(criteria *models.AuditSearchCriteria) error {
+	errs := &errors.MultiError{}
+
+	if criteria.Limit < 1 {
+		criteria.Limit = 10
+	}
+	if criteria.Limit > maxSearchLimit {
+		errs.Add(errors.NewFieldError("limit", fmt.Sprintf("limit must not exceed %d", maxSearchLimit)))
+	}
+	if criteria.Offset < 0 {
+		errs.Add(errors.NewFieldError("offset", "offset must not be negative"))
 	}
 
-	return nil
+	return errs.ErrorOrNil()
 }
 
 // ValidateSearch validates employee search criteria
 func (v *EmployeeValidator) ValidateSearch(ctx context.Context, criteria *models.EmployeeSearchCriteria) error {
+	errs := &errors.MultiError{}
+
 	if criteria.TenantID == "" {
-		return fmt.Errorf("tenant ID is required")
+		errs.Add(errors.NewFieldError("tenantId", "tenant ID is required"))
 	}
 
 	// Validate limit and offset
@@ -129,30 +170,131 @@ func (v *EmployeeValidator) ValidateSearch(ctx context.Context, criteria *models
 		criteria.Limit = 10
 	}
 
+	if criteria.Limit > maxSearchLimit {
+		errs.Add(errors.NewFieldError("limit", fmt.Sprintf("limit must not exceed %d", maxSearchLimit)))
+	}
+
 	if criteria.Offset < 0 {
 		criteria.Offset = 0
 	}
 
+	if criteria.Phone != "" && !phoneRegex.MatchString(criteria.Phone) {
+		errs.Add(errors.NewFieldError("phone", "invalid mobile number format. Must be a 10-digit number starting with 6-9"))
+	}
+
+	if criteria.IncludeDescendants && len(criteria.BoundaryCodes) == 0 {
+		errs.Add(errors.NewFieldError("includeDescendants", "includeDescendants requires boundaryCodes"))
+	}
+
+	if len(criteria.BoundaryCodes) > maxSearchBoundaryCodes {
+		errs.Add(errors.NewFieldError("boundaryCodes", fmt.Sprintf("at most %d boundary codes can be searched at once", maxSearchBoundaryCodes)))
+	}
+
 	// Validate sort order
 	if criteria.SortBy != "" {
 		validSortFields := map[string]bool{
-			"code":        true,
-			"createdAt":   true,
-			"updatedAt":   true,
+			"code":         true,
+			"createdAt":    true,
+			"updatedAt":    true,
 			"employeeType": true,
-			"status":      true,
+			"status":       true,
 		}
 
 		if !validSortFields[criteria.SortBy] {
-			return fmt.Errorf("invalid sort field: %s", criteria.SortBy)
+			errs.Add(errors.NewFieldError("sortBy", fmt.Sprintf("invalid sort field: %s", criteria.SortBy)))
+		}
+	}
+
+	if criteria.SortOrder != "" && criteria.SortOrder != "asc" && criteria.SortOrder != "desc" {
+		errs.Add(errors.NewFieldError("sortOrder", fmt.Sprintf("invalid sort order: %s. Must be 'asc' or 'desc'", criteria.SortOrder)))
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// validateEmployee collects the field errors shared by create and replace
+func (v *EmployeeValidator) validateEmployee(ctx context.Context, emp *models.Employee, errs *errors.MultiError) {
+	// Required fields validation
+	if emp.TenantID == "" {
+		errs.Add(errors.NewFieldError("tenantId", "tenant ID is required"))
+	}
+
+	if emp.EmployeeType == "" {
+		errs.Add(errors.NewFieldError("employeeType", "employee type is required"))
+	} else if !isValidEmployeeType(emp.EmployeeType) {
+		errs.Add(errors.NewFieldError("employeeType", fmt.Sprintf("invalid employee type: %s. Must be one of: PERMANENT, CONTRACT, TEMPORARY", emp.EmployeeType)))
+	}
+
+	if emp.Status != "" && !isValidEmployeeStatus(emp.Status) {
+		errs.Add(errors.NewFieldError("status", fmt.Sprintf("invalid employee status: %s. Must be one of: ACTIVE, INACTIVE, SUSPENDED", emp.Status)))
+	}
+
+	if emp.Department == "" {
+		errs.Add(errors.NewFieldError("department", "department is required"))
+	}
+
+	if emp.Designation == "" {
+		errs.Add(errors.NewFieldError("designation", "designation is required"))
+	}
+
+	// Validate field formats and constraints
+	if emp.Code != "" {
+		if len(emp.Code) < 2 || len(emp.Code) > 64 {
+			errs.Add(errors.NewFieldError("code", "code must be between 2 and 64 characters"))
 		}
 
-		if criteria.SortOrder != "" && criteria.SortOrder != "asc" && criteria.SortOrder != "desc" {
-			return fmt.Errorf("invalid sort order: %s. Must be 'asc' or 'desc'", criteria.SortOrder)
+		// Check for duplicate employee code
+		existing, err := v.repo.FindByCode(ctx, emp.Code, emp.TenantID)
+		if err == nil && existing != nil && existing.ID != emp.ID {
+			errs.Add(errors.NewFieldError("code", fmt.Sprintf("employee with code %s already exists", emp.Code)))
 		}
 	}
 
-	return nil
+	v.validatePersonalDetails(ctx, emp, errs)
+}
+
+// validatePersonalDetails collects field errors for the optional personal details
+// and enforces that a phone number is unique within a tenant
+func (v *EmployeeValidator) validatePersonalDetails(ctx context.Context, emp *models.Employee, errs *errors.MultiError) {
+	if emp.MobileNumber != "" {
+		if !phoneRegex.MatchString(emp.MobileNumber) {
+			errs.Add(errors.NewFieldError("mobileNumber", "invalid mobile number format. Must be a 10-digit number starting with 6-9"))
+		} else {
+			existing, err := v.repo.FindByMobileNumber(ctx, emp.MobileNumber, emp.TenantID)
+			if err == nil && existing != nil && existing.ID != emp.ID {
+				errs.Add(errors.NewFieldError("mobileNumber", "another employee already uses this mobile number"))
+			}
+		}
+	}
+
+	if emp.Email != "" && !emailRegex.MatchString(emp.Email) {
+		errs.Add(errors.NewFieldError("email", "invalid email format"))
+	}
+
+	if emp.Gender != "" && !isValidGender(emp.Gender) {
+		errs.Add(errors.NewFieldError("gender", fmt.Sprintf("invalid gender: %s. Must be one of: MALE, FEMALE, TRANSGENDER", emp.Gender)))
+	}
+
+	if emp.PanNumber != "" && !panRegex.MatchString(emp.PanNumber) {
+		errs.Add(errors.NewFieldError("panNumber", "invalid PAN format. Must look like ABCDE1234F"))
+	}
+
+	if emp.AadhaarNumber != "" && !aadhaarRegex.MatchString(emp.AadhaarNumber) {
+		errs.Add(errors.NewFieldError("aadhaarNumber", "invalid Aadhaar number. Must be a 12-digit number"))
+	}
+
+	if emp.DateOfBirth != nil && emp.DateOfBirth.After(time.Now()) {
+		errs.Add(errors.NewFieldError("dateOfBirth", "date of birth must be in the past"))
+	}
+
+	if emp.DateOfRetirement != nil {
+		if emp.DateOfBirth != nil && !emp.DateOfRetirement.After(*emp.DateOfBirth) {
+			errs.Add(errors.NewFieldError("dateOfRetirement", "date of retirement must be after date of birth"))
+		}
+		if emp.DateOfAppointment != nil && !emp.DateOfRetirement.After(*emp.DateOfAppointment) {
+			errs.Add(errors.NewFieldError("dateOfRetirement", "date of retirement must be after date of appointment"))
+		}
+	}
 }
 
 // normalizeValidationErrors converts validation errors to a more user-friendly format
@@ -220,10 +362,19 @@ func isValidEmployeeType(empType string) bool {
 	return validTypes[empType]
 }
 
+func isValidGender(gender string) bool {
+	validGenders := map[string]bool{
+		"MALE":        true,
+		"FEMALE":      true,
+		"TRANSGENDER": true,
+	}
+	return validGenders[gender]
+}
+
 func isValidEmployeeStatus(status string) bool {
 	validStatuses := map[string]bool{
-		"ACTIVE":   true,
-		"INACTIVE": true,
+		"ACTIVE":    true,
+		"INACTIVE":  true,
 		"SUSPENDED": true,
 	}
 	return validStatuses[status]
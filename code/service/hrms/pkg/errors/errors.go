@@ -1,8 +1,10 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
 	"runtime"
+	"strings"
 )
 
 // Error represents a structured error with stack trace
@@ -30,6 +32,13 @@ func (e *Error) Error() string {
 // Unwrap implements the Unwrap method for error unwrapping
 func (e *Error) Unwrap() error { return e.Err }
 
+// Is matches errors by code, so the standard errors.Is finds a code anywhere in a
+// chain of wrapped errors
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t != nil && t.Code == e.Code
+}
+
 // New creates a new error
 func New(code, message string) *Error {
 	return &Error{
@@ -52,22 +61,54 @@ func Wrap(err error, code, message string) *Error {
 	}
 }
 
-// WithDescription adds a description to the error
+// WithDescription returns a copy of the error with the given description.
+// Copying keeps the shared sentinel errors below unchanged.
 func (e *Error) WithDescription(desc string) *Error {
-	e.Description = desc
-	return e
+	c := *e
+	c.Description = desc
+	return &c
 }
 
-// WithParams adds parameters to the error
+// WithParams returns a copy of the error with the given parameters
 func (e *Error) WithParams(params interface{}) *Error {
-	e.Params = params
-	return e
+	c := *e
+	c.Params = params
+	return &c
 }
 
-// WithOperation adds operation context to the error
+// WithParam returns a copy of the error with one more parameter. The entries of map
+// params are kept; params of any other type are kept under the key "params".
+func (e *Error) WithParam(key string, value interface{}) *Error {
+	params := e.ParamMap()
+	params[key] = value
+	return e.WithParams(params)
+}
+
+// ParamMap returns a copy of the params as a map, with params that are not a map under
+// the key "params"
+func (e *Error) ParamMap() map[string]interface{} {
+	params := make(map[string]interface{})
+	switch p := e.Params.(type) {
+	case nil:
+	case map[string]interface{}:
+		for k, v := range p {
+			params[k] = v
+		}
+	case map[string]string:
+		for k, v := range p {
+			params[k] = v
+		}
+	default:
+		params["params"] = p
+	}
+	return params
+}
+
+// WithOperation returns a copy of the error with the given operation context
 func (e *Error) WithOperation(op string) *Error {
-	e.Op = op
-	return e
+	c := *e
+	c.Op = op
+	return &c
 }
 
 // Common error codes
@@ -91,10 +132,67 @@ var (
 	ErrJurisdictionNotFound = New("JURISDICTION_NOT_FOUND", "Jurisdiction not found")
 	ErrJurisdictionExists   = New("JURISDICTION_EXISTS", "Jurisdiction already exists")
 
+	// Assignment errors
+	ErrAssignmentOverlap         = New("ASSIGNMENT_OVERLAP", "Assignment periods overlap")
+	ErrMultipleActiveAssignments = New("MULTIPLE_ACTIVE_ASSIGNMENTS", "Employee cannot have more than one active assignment")
+	ErrEmployeeHasAssignments    = New("EMPLOYEE_HAS_ASSIGNMENTS", "Employee has assignments and cannot be deleted")
+
 	// Database errors
 	ErrDatabase = New("DATABASE_ERROR", "A database error occurred")
 )
 
+// MultiError collects several errors that are reported together, such as every
+// invalid field of a request
+type MultiError struct {
+	Errors []*Error
+}
+
+// Error implements the error interface
+func (m *MultiError) Error() string {
+	msgs := make([]string, 0, len(m.Errors))
+	for _, e := range m.Errors {
+		msgs = append(msgs, e.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Add appends an error, flattening nested MultiErrors. Nil errors are ignored.
+func (m *MultiError) Add(err error) {
+	switch e := err.(type) {
+	case nil:
+		return
+	case *MultiError:
+		m.Errors = append(m.Errors, e.Errors...)
+	case *Error:
+		m.Errors = append(m.Errors, e)
+	default:
+		m.Errors = append(m.Errors, Wrap(err, ErrInternalServer.Code, err.Error()))
+	}
+}
+
+// Unwrap returns the collected errors so the standard errors.Is and errors.As look
+// through each of them
+func (m *MultiError) Unwrap() []error {
+	errs := make([]error, 0, len(m.Errors))
+	for _, e := range m.Errors {
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+// ErrorOrNil returns nil when no errors were collected
+func (m *MultiError) ErrorOrNil() error {
+	if len(m.Errors) == 0 {
+		return nil
+	}
+	return m
+}
+
+// NewFieldError creates a validation error for a single request field
+func NewFieldError(field, message string) *Error {
+	return New(ErrValidationFailed.Code, message).WithParams(map[string]string{"field": field})
+}
+
 // ErrorResponse represents the JSON response for errors
 type ErrorResponse struct {
 	Error ErrorDetails `json:"error"`
@@ -129,13 +227,9 @@ func ToResponse(err error) ErrorResponse {
 	}
 }
 
-// Is checks if the error is of a specific error code
+// Is checks if the error, or any error it wraps, is of a specific error code
 func Is(err error, target *Error) bool {
-	e, ok := err.(*Error)
-	if !ok {
-		return false
-	}
-	return e.Code == target.Code
+	return stderrors.Is(err, target)
 }
 
 // getStack returns the current stack trace as a string
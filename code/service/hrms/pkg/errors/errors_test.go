@@ -0,0 +1,109 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+)
+
+func TestIs(t *testing.T) {
+	multi := &MultiError{}
+	multi.Add(NewFieldError("code", "code is required"))
+	multi.Add(ErrNotFound.WithDescription("employee not found"))
+
+	tests := []struct {
+		name   string
+		err    error
+		target *Error
+		want   bool
+	}{
+		{name: "nil error", err: nil, target: ErrNotFound, want: false},
+		{name: "same sentinel", err: ErrNotFound, target: ErrNotFound, want: true},
+		{name: "copy with description", err: ErrNotFound.WithDescription("employee not found").WithOperation("Get"), target: ErrNotFound, want: true},
+		{name: "other code", err: ErrEmployeeExists, target: ErrNotFound, want: false},
+		{name: "wrapped by another code", err: Wrap(ErrNotFound, "DATABASE_ERROR", "lookup failed"), target: ErrNotFound, want: true},
+		{name: "outer code of a wrapped error", err: Wrap(ErrNotFound, "DATABASE_ERROR", "lookup failed"), target: ErrDatabase, want: true},
+		{name: "wrapped with fmt.Errorf", err: fmt.Errorf("load employee: %w", ErrNotFound), target: ErrNotFound, want: true},
+		{name: "wrapped twice", err: fmt.Errorf("outer: %w", Wrap(fmt.Errorf("inner: %w", ErrJurisdictionExists), "INTERNAL_ERROR", "failed")), target: ErrJurisdictionExists, want: true},
+		{name: "plain error", err: stderrors.New("connection reset"), target: ErrDatabase, want: false},
+		{name: "plain error wrapped by a code", err: Wrap(stderrors.New("connection reset"), "DATABASE_ERROR", "failed"), target: ErrDatabase, want: true},
+		{name: "second entry of a multi error", err: multi, target: ErrNotFound, want: true},
+		{name: "first entry of a multi error", err: multi, target: ErrValidationFailed, want: true},
+		{name: "absent from a multi error", err: multi, target: ErrEmployeeExists, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Is(tt.err, tt.target); got != tt.want {
+				t.Fatalf("Is(%v, %s) = %v, want %v", tt.err, tt.target.Code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiErrorAdd(t *testing.T) {
+	nested := &MultiError{}
+	nested.Add(NewFieldError("a", "a is required"))
+	nested.Add(NewFieldError("b", "b is required"))
+
+	tests := []struct {
+		name      string
+		add       []error
+		wantCodes []string
+	}{
+		{name: "nothing", add: nil},
+		{name: "nil is ignored", add: []error{nil}},
+		{name: "structured error", add: []error{ErrNotFound}, wantCodes: []string{"NOT_FOUND"}},
+		{name: "nested multi error is flattened", add: []error{nested, ErrNotFound}, wantCodes: []string{"VALIDATION_ERROR", "VALIDATION_ERROR", "NOT_FOUND"}},
+		{name: "plain error becomes internal", add: []error{stderrors.New("boom")}, wantCodes: []string{"INTERNAL_ERROR"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &MultiError{}
+			for _, err := range tt.add {
+				m.Add(err)
+			}
+			if len(tt.wantCodes) == 0 {
+				if m.ErrorOrNil() != nil {
+					t.Fatalf("ErrorOrNil = %v, want nil", m.ErrorOrNil())
+				}
+				return
+			}
+			if len(m.Errors) != len(tt.wantCodes) {
+				t.Fatalf("got %d errors, want %d", len(m.Errors), len(tt.wantCodes))
+			}
+			for i, code := range tt.wantCodes {
+				if m.Errors[i].Code != code {
+					t.Fatalf("error %d code = %s, want %s", i, m.Errors[i].Code, code)
+				}
+			}
+		})
+	}
+}
+
+func TestWithParam(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    *Error
+		want   string
+		wantOf string // params of err after the call, which must be unchanged
+	}{
+		{name: "no params", err: ErrNotFound, want: "map[index:1]", wantOf: "<nil>"},
+		{name: "field error", err: NewFieldError("code", "code is required"), want: "map[field:code index:1]", wantOf: "map[field:code]"},
+		{name: "interface map", err: ErrNotFound.WithParams(map[string]interface{}{"index": 0, "id": "e-1"}), want: "map[id:e-1 index:1]", wantOf: "map[id:e-1 index:0]"},
+		{name: "params that are not a map", err: ErrNotFound.WithParams([]string{"WARD-1"}), want: "map[index:1 params:[WARD-1]]", wantOf: "[WARD-1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.err.WithParam("index", 1)
+			if params := fmt.Sprint(got.Params); params != tt.want {
+				t.Fatalf("params = %s, want %s", params, tt.want)
+			}
+			if params := fmt.Sprint(tt.err.Params); params != tt.wantOf {
+				t.Fatalf("original params = %s, want %s", params, tt.wantOf)
+			}
+		})
+	}
+}